@@ -66,6 +66,10 @@ PROGRAMS=(
     "cmd/multicast-test:multicast-test:Multicast overlay test"
     "cmd/emergency-test:emergency-test:Emergency priority test"
     "cmd/music-broadcast:music-broadcast:Music file broadcaster"
+    "cmd/mesh-browse:mesh-browse:Mesh-wide station discovery"
+    "cmd/call-test:call-test:CQ and selective calling test"
+    "cmd/relay:relay:Relay that re-broadcasts an upstream station"
+    "cmd/station:station:Several streams from one process"
 )
 
 SUCCESS=0
@@ -100,6 +104,10 @@ if [ $FAILED -eq 0 ]; then
     echo "  ./multicast-test   - Test multicast overlay"
     echo "  ./emergency-test   - Test emergency features"
     echo "  ./music-broadcast  - Broadcast music files (MP3)"
+    echo "  ./mesh-browse      - Discover stations across the mesh"
+    echo "  ./call-test        - Send and answer CQ/selective calls"
+    echo "  ./relay            - Re-broadcast a station to more listeners"
+    echo "  ./station          - Run several streams on one port"
     echo
     echo "Quick start:"
     echo "  ./meshradio --help              # Show help"
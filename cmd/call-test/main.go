@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/meshradio/meshradio/internal/listener"
+	"github.com/meshradio/meshradio/pkg/calling"
+	"github.com/meshradio/meshradio/pkg/discovery"
+	"github.com/meshradio/meshradio/pkg/yggdrasil"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(1)
+	}
+
+	mode := os.Args[1]
+	fs := flag.NewFlagSet(mode, flag.ExitOnError)
+	callsign := fs.String("callsign", "STATION", "Your callsign")
+	port := fs.Int("port", calling.DefaultPort, "Local call port")
+	group := fs.String("group", "community", "Group to call on")
+	groups := fs.String("groups", "", "Comma-separated groups to answer CQ on (answer mode, empty = all)")
+	message := fs.String("msg", "", "Message sent with the call")
+	streamPort := fs.Int("stream-port", 0, "Port of your running broadcast (0 = not streaming)")
+	targets := fs.String("targets", "", "Comma-separated call targets ([ipv6]:port or ipv6)")
+	seeds := fs.String("seeds", "", "Discovery seeds used to find targets ([ipv6]:port or ipv6)")
+	to := fs.String("to", "", "Callsign to call (call mode)")
+	timeout := fs.Int("timeout", 30, "Seconds to wait for answers")
+	fs.Parse(os.Args[2:])
+
+	ipv6, err := yggdrasil.GetLocalIPv6()
+	if err != nil {
+		fmt.Printf("Warning: Could not get Yggdrasil IPv6: %v\n", err)
+		fmt.Println("Using localhost for testing")
+		ipv6 = net.IPv6loopback
+	}
+
+	cfg := calling.Config{
+		Callsign:   *callsign,
+		IPv6:       ipv6,
+		Port:       *port,
+		StreamPort: *streamPort,
+	}
+	if *groups != "" {
+		cfg.Groups = strings.Split(*groups, ",")
+	}
+
+	mgr, err := calling.NewManager(cfg)
+	if err != nil {
+		log.Fatalf("Failed to create call manager: %v", err)
+	}
+	if err := mgr.Start(); err != nil {
+		log.Fatalf("Failed to start call manager: %v", err)
+	}
+	defer mgr.Stop()
+
+	fmt.Printf("Callsign: %s | IPv6: %s | Call port: %d\n\n", *callsign, ipv6, *port)
+
+	switch mode {
+	case "cq":
+		addrs := parseTargets(*targets)
+		cache := browse(ipv6, *seeds, *group)
+		if cache != nil {
+			addrs = append(addrs, calling.TargetsFromCache(cache, *group)...)
+		}
+		if _, err := mgr.CQ(*group, *message, addrs); err != nil {
+			log.Fatalf("CQ failed: %v", err)
+		}
+		waitReplies(mgr, time.Duration(*timeout)*time.Second)
+
+	case "call":
+		if *to == "" && *targets == "" {
+			log.Fatal("call mode needs -to and/or -targets")
+		}
+		addrs := parseTargets(*targets)
+		if len(addrs) == 0 {
+			cache := browse(ipv6, *seeds, "")
+			if cache == nil {
+				log.Fatal("no -targets given and no -seeds to look up the callsign")
+			}
+			addr, ok := calling.ResolveCallsign(cache, *to)
+			if !ok {
+				log.Fatalf("Station %s not found on the mesh", *to)
+			}
+			addrs = append(addrs, addr)
+		}
+		if _, err := mgr.CallStation(addrs[0], *to, *group, *message); err != nil {
+			log.Fatalf("Call failed: %v", err)
+		}
+		waitReplies(mgr, time.Duration(*timeout)*time.Second)
+
+	case "answer":
+		answer(mgr)
+
+	default:
+		printUsage()
+		os.Exit(1)
+	}
+}
+
+// parseTargets parses the -targets list
+func parseTargets(s string) []*net.UDPAddr {
+	addrs := make([]*net.UDPAddr, 0)
+	if s == "" {
+		return addrs
+	}
+	for _, t := range strings.Split(s, ",") {
+		addr, err := calling.ParseTarget(t)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
+// browse fills a discovery cache from the given seeds (nil when there are none)
+func browse(ipv6 net.IP, seeds, group string) *discovery.Cache {
+	if seeds == "" {
+		return nil
+	}
+
+	var seedAddrs []*net.UDPAddr
+	for _, s := range strings.Split(seeds, ",") {
+		addr, err := discovery.ParseSeed(s)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		seedAddrs = append(seedAddrs, addr)
+	}
+
+	client, err := discovery.NewClient(ipv6, 0, nil)
+	if err != nil {
+		log.Fatalf("Failed to create discovery client: %v", err)
+	}
+	defer client.Close()
+
+	fmt.Println("🔍 Looking up stations...")
+	if _, err := client.Browse(discovery.BrowseOptions{
+		Seeds:   seedAddrs,
+		Group:   group,
+		Timeout: 3 * time.Second,
+	}); err != nil {
+		log.Fatalf("Browse failed: %v", err)
+	}
+	return client.Cache()
+}
+
+// waitReplies prints answers until the timeout expires
+func waitReplies(mgr *calling.Manager, timeout time.Duration) {
+	fmt.Printf("Waiting %v for answers...\n\n", timeout)
+	deadline := time.After(timeout)
+	for {
+		select {
+		case r := <-mgr.Replies():
+			if r.Accepted {
+				fmt.Printf("✅ %s (%s) accepted\n", r.From, r.FromIPv6)
+			} else {
+				fmt.Printf("❌ %s (%s) declined\n", r.From, r.FromIPv6)
+			}
+		case <-deadline:
+			return
+		}
+	}
+}
+
+// answer prompts for every incoming call until interrupted
+func answer(mgr *calling.Manager) {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+
+	stdin := bufio.NewReader(os.Stdin)
+	var current *listener.Listener
+
+	fmt.Println("Waiting for calls... (Ctrl+C to stop)")
+	for {
+		select {
+		case call := <-mgr.Incoming():
+			fmt.Printf("\n📞 %s call from %s (%s) on '%s'\n", call.Type, call.From, call.FromIPv6, call.Group)
+			if call.Message != "" {
+				fmt.Printf("   \"%s\"\n", call.Message)
+			}
+			fmt.Print("Accept? [y/N] ")
+			line, _ := stdin.ReadString('\n')
+
+			if strings.ToLower(strings.TrimSpace(line)) != "y" {
+				if err := call.Decline(); err != nil {
+					fmt.Printf("Decline failed: %v\n", err)
+				}
+				continue
+			}
+
+			if current != nil {
+				current.Stop()
+				current = nil
+			}
+			l, err := call.Accept()
+			if err != nil {
+				fmt.Printf("Accept failed: %v\n", err)
+				continue
+			}
+			if l == nil {
+				fmt.Println("✅ Accepted (caller is not streaming)")
+				continue
+			}
+			current = l
+			fmt.Printf("🎧 Listening to %s\n", call.From)
+
+		case <-sigChan:
+			if current != nil {
+				current.Stop()
+			}
+			return
+		}
+	}
+}
+
+func printUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  call-test cq -callsign W1AW -group community -targets '[200:1234::1]' -msg 'CQ CQ'")
+	fmt.Println("  call-test cq -callsign W1AW -group community -seeds '[200:1234::1]:8799'")
+	fmt.Println("  call-test call -callsign W1AW -to K6ABC -seeds '[200:1234::1]:8799' -stream-port 8798")
+	fmt.Println("  call-test answer -callsign K6ABC -groups community,emergency")
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"crypto/ed25519"
 	"flag"
 	"fmt"
 	"net"
@@ -13,6 +14,9 @@ import (
 	"github.com/meshradio/meshradio/internal/listener"
 	"github.com/meshradio/meshradio/pkg/audio"
 	"github.com/meshradio/meshradio/pkg/emergency"
+	"github.com/meshradio/meshradio/pkg/encryption"
+	"github.com/meshradio/meshradio/pkg/network"
+	"github.com/meshradio/meshradio/pkg/signing"
 	"github.com/meshradio/meshradio/pkg/yggdrasil"
 )
 
@@ -59,9 +63,18 @@ func printUsage() {
 	fmt.Println("  listen-autotune      - Listen with auto-tune enabled")
 	fmt.Println("  listen-manual        - Listen without auto-tune")
 	fmt.Println()
+	fmt.Println("Signing (listeners only alert on emergencies signed by a trusted key):")
+	fmt.Println("  broadcast-* -key station.key    Sign with a station key (created if missing)")
+	fmt.Println("  broadcast-* -ygg-key            Sign with the Yggdrasil node key")
+	fmt.Println("  listen-*    -trust trusted.txt  Trusted keys, one \"CALLSIGN PUBLICKEY\" per line")
+	fmt.Println("  listen-*    -allow-unsigned     Also alert on unsigned emergencies (insecure)")
+	fmt.Println()
+	fmt.Println("Private groups (both sides):")
+	fmt.Println("  -keys keys.txt                  Group keys, one \"GROUP KEYID KEY-OR-PASSPHRASE\" per line")
+	fmt.Println()
 	fmt.Println("Example:")
-	fmt.Println("  Terminal 1: emergency-test broadcast-critical")
-	fmt.Println("  Terminal 2: emergency-test listen-manual")
+	fmt.Println("  Terminal 1: emergency-test broadcast-critical -key station.key")
+	fmt.Println("  Terminal 2: emergency-test listen-manual -target <ipv6> -trust trusted.txt")
 }
 
 func broadcastCritical() {
@@ -81,6 +94,30 @@ func broadcastNormal() {
 }
 
 func broadcast(group, callsign string, port int) {
+	fs := flag.NewFlagSet("broadcast", flag.ExitOnError)
+	keyFile := fs.String("key", "", "Station key file for signing (created if missing)")
+	yggKey := fs.Bool("ygg-key", false, "Sign with the Yggdrasil node key")
+	yggConfig := fs.String("ygg-config", signing.DefaultYggdrasilConfig, "Yggdrasil config file (with -ygg-key)")
+	keysFile := fs.String("keys", "", "Group keys for private groups")
+	multicastIf := fs.String("multicast", "", "Also send natively to the IPv6 multicast group on this interface (\"any\" = system choice)")
+	fs.Parse(os.Args[2:])
+
+	keyring := loadKeyring(*keysFile)
+	mcast := multicastConfig(*multicastIf)
+
+	var signingKey ed25519.PrivateKey
+	var err error
+	switch {
+	case *yggKey:
+		signingKey, err = signing.LoadYggdrasilKey(*yggConfig)
+	case *keyFile != "":
+		signingKey, err = signing.LoadOrCreateKey(*keyFile)
+	}
+	if err != nil {
+		fmt.Printf("Error loading signing key: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Get local IPv6
 	ipv6, err := yggdrasil.GetLocalIPv6()
 	if err != nil {
@@ -109,6 +146,14 @@ func broadcast(group, callsign string, port int) {
 	fmt.Printf("╚══════════════════════════════════════════════════════════════╝\n")
 	fmt.Println()
 
+	if signingKey != nil {
+		fmt.Println("Listeners must trust this station key. Add to their trust file:")
+		fmt.Printf("  %s %s\n\n", callsign, signing.PublicKeyHex(signingKey))
+	} else {
+		fmt.Println("⚠️  Not signing: listeners will not raise alerts for this broadcast")
+		fmt.Println()
+	}
+
 	// Create broadcaster config
 	cfg := broadcaster.Config{
 		Callsign: callsign,
@@ -121,6 +166,9 @@ func broadcast(group, callsign string, port int) {
 			FrameSize:  960,
 			Bitrate:    24000,
 		},
+		SigningKey: signingKey,
+		Keyring:    keyring,
+		Multicast:  mcast,
 	}
 
 	// Create and start broadcaster
@@ -162,14 +210,48 @@ func listen(autoTune bool) {
 	var targetPort int
 	var group string
 	var callsign string
+	var trustFile string
+	var allowUnsigned bool
+	var keysFile string
 
 	fs := flag.NewFlagSet("listen", flag.ExitOnError)
 	fs.StringVar(&targetAddr, "target", "", "Target broadcaster IPv6 address")
 	fs.IntVar(&targetPort, "port", 8790, "Target broadcaster port")
 	fs.StringVar(&group, "group", "emergency", "Multicast group to join")
 	fs.StringVar(&callsign, "callsign", "LISTENER-TEST", "Your callsign")
+	fs.StringVar(&trustFile, "trust", "", "Trusted station keys (\"CALLSIGN PUBLICKEY\" per line)")
+	fs.BoolVar(&allowUnsigned, "allow-unsigned", false, "Also alert on unsigned emergency broadcasts (insecure)")
+	fs.StringVar(&keysFile, "keys", "", "Group keys for private groups")
+	keyFile := fs.String("key", "", "Listener key file for signing subscriptions (created if missing)")
+	multicastIf := fs.String("multicast", "", "Join the native IPv6 multicast group on this interface when offered (\"any\" = system choice)")
 	fs.Parse(os.Args[2:])
 
+	keyring := loadKeyring(keysFile)
+	mcast := multicastConfig(*multicastIf)
+
+	var trustStore *signing.TrustStore
+	if trustFile != "" {
+		var err error
+		trustStore, err = signing.LoadTrustStore(trustFile)
+		if err != nil {
+			fmt.Printf("Error loading trust store: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Loaded %d trusted station(s) from %s\n", trustStore.Len(), trustFile)
+	}
+
+	var signingKey ed25519.PrivateKey
+	if *keyFile != "" {
+		key, err := signing.LoadOrCreateKey(*keyFile)
+		if err != nil {
+			fmt.Printf("Error loading signing key: %v\n", err)
+			os.Exit(1)
+		}
+		signingKey = key
+		fmt.Println("Stations with callsign rules must trust this key. Add to their trust file:")
+		fmt.Printf("  %s %s\n\n", callsign, signing.PublicKeyHex(signingKey))
+	}
+
 	if targetAddr == "" {
 		fmt.Println("Error: -target flag is required")
 		fmt.Println()
@@ -232,6 +314,11 @@ func listen(autoTune bool) {
 			FrameSize:  960,
 			Bitrate:    24000,
 		},
+		TrustStore:          trustStore,
+		AllowUnsignedAlerts: allowUnsigned,
+		SigningKey:          signingKey,
+		Keyring:             keyring,
+		Multicast:           mcast,
 	}
 
 	// Create and start listener
@@ -277,3 +364,35 @@ func listen(autoTune bool) {
 		}
 	}
 }
+
+// loadKeyring loads the group keys file (nil if none given)
+// multicastConfig builds the native multicast config for the -multicast flag
+// Returns nil (unicast only) when the flag is empty
+func multicastConfig(ifname string) *network.MulticastConfig {
+	switch ifname {
+	case "":
+		return nil
+	case "any":
+		return &network.MulticastConfig{}
+	}
+
+	ifi, err := net.InterfaceByName(ifname)
+	if err != nil {
+		fmt.Printf("Error finding multicast interface: %v\n", err)
+		os.Exit(1)
+	}
+	return &network.MulticastConfig{Interface: ifi}
+}
+
+func loadKeyring(path string) *encryption.Keyring {
+	if path == "" {
+		return nil
+	}
+
+	keyring, err := encryption.LoadKeyring(path)
+	if err != nil {
+		fmt.Printf("Error loading keys: %v\n", err)
+		os.Exit(1)
+	}
+	return keyring
+}
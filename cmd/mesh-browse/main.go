@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/meshradio/meshradio/pkg/discovery"
+	"github.com/meshradio/meshradio/pkg/yggdrasil"
+)
+
+func main() {
+	seeds := flag.String("seeds", "", "Comma-separated nodes to ask ([ipv6]:port or ipv6)")
+	peers := flag.Bool("peers", true, "Also ask directly connected Yggdrasil peers")
+	group := flag.String("group", "", "Filter by group (empty = all)")
+	port := flag.Int("port", 0, "Local UDP port for responses (0 = any)")
+	timeout := flag.Int("timeout", 3, "Browse timeout in seconds")
+	flag.Parse()
+
+	// Parse seed list
+	var seedAddrs []*net.UDPAddr
+	if *seeds != "" {
+		for _, s := range strings.Split(*seeds, ",") {
+			addr, err := discovery.ParseSeed(s)
+			if err != nil {
+				log.Fatalf("%v", err)
+			}
+			seedAddrs = append(seedAddrs, addr)
+		}
+	}
+
+	// Get local IPv6 (responses are sent back here)
+	ipv6, err := yggdrasil.GetLocalIPv6()
+	if err != nil {
+		fmt.Printf("Warning: Could not get Yggdrasil IPv6: %v\n", err)
+		fmt.Println("Using localhost for testing")
+		ipv6 = net.IPv6loopback
+	}
+
+	fmt.Printf("Mesh Station Discovery\n")
+	fmt.Printf("======================\n")
+	fmt.Printf("Seeds: %d | Peers: %v\n", len(seedAddrs), *peers)
+	if *group != "" {
+		fmt.Printf("Filter: group=%s\n", *group)
+	}
+	fmt.Printf("Timeout: %d seconds\n", *timeout)
+	fmt.Printf("Asking mesh nodes for stations...\n\n")
+
+	client, err := discovery.NewClient(ipv6, *port, nil)
+	if err != nil {
+		log.Fatalf("Failed to create discovery client: %v", err)
+	}
+	defer client.Close()
+
+	records, err := client.Browse(discovery.BrowseOptions{
+		Seeds:   seedAddrs,
+		Peers:   *peers,
+		Group:   *group,
+		Timeout: time.Duration(*timeout) * time.Second,
+	})
+	if err != nil {
+		log.Fatalf("Browse failed: %v", err)
+	}
+
+	// Display results
+	if len(records) == 0 {
+		fmt.Println("No stations found.")
+		fmt.Println("\nTips:")
+		fmt.Println("- Pass a node running a broadcaster with -seeds '[200:1234::1]:8799'")
+		fmt.Println("- Check that Yggdrasil is running (yggdrasilctl getPeers)")
+		return
+	}
+
+	fmt.Printf("Found %d station(s):\n\n", len(records))
+	for i, r := range records {
+		fmt.Printf("%d. %s\n", i+1, discovery.FormatRecord(r))
+		if r.IPv6 != nil {
+			fmt.Printf("   → Listen with: ./emergency-test listen-manual -target %s -port %d -group %s\n",
+				r.IPv6, r.Port, r.Group)
+		}
+		fmt.Println()
+	}
+}
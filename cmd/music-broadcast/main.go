@@ -8,35 +8,42 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
-	"strings"
 	"syscall"
 	"time"
 
-	"github.com/hajimehoshi/go-mp3"
 	"github.com/meshradio/meshradio/internal/broadcaster"
+	"github.com/meshradio/meshradio/pkg/archive"
 	"github.com/meshradio/meshradio/pkg/audio"
-	"github.com/meshradio/meshradio/pkg/multicast"
+	"github.com/meshradio/meshradio/pkg/encryption"
+	"github.com/meshradio/meshradio/pkg/playlist"
+	"github.com/meshradio/meshradio/pkg/protocol"
 	"github.com/meshradio/meshradio/pkg/yggdrasil"
 )
 
 var (
-	musicDir  = flag.String("dir", "", "Music directory to scan (default: ~/Music)")
-	callsign  = flag.String("callsign", "MUSIC-DJ", "Your callsign")
-	port      = flag.Int("port", 8799, "Broadcast port (default: 8799 standard broadcaster port)")
-	group     = flag.String("group", "default", "Multicast group")
-	shuffle   = flag.Bool("shuffle", false, "Shuffle playlist")
-	loop      = flag.Bool("loop", true, "Loop playlist")
-	advertise = flag.Bool("advertise", true, "Advertise via mDNS")
+	musicDir    = flag.String("dir", "", "Music directory to scan (default: ~/Music)")
+	callsign    = flag.String("callsign", "MUSIC-DJ", "Your callsign")
+	port        = flag.Int("port", 8799, "Broadcast port (default: 8799 standard broadcaster port)")
+	group       = flag.String("group", "default", "Multicast group")
+	shuffle     = flag.Bool("shuffle", false, "Shuffle playlist")
+	loop        = flag.Bool("loop", true, "Loop playlist")
+	advertise   = flag.Bool("advertise", true, "Advertise via mDNS")
+	keysFile    = flag.String("keys", "", "Group keys file for private groups (\"GROUP KEYID KEY\" per line)")
+	talkover    = flag.Bool("talkover", false, "Mix in the microphone; the music ducks while you talk")
+	duckDB      = flag.Float64("duck", audio.DefaultDuckDepth, "How far the music drops under your voice, in dB (with -talkover)")
+	schedFile   = flag.String("schedule", "", "Follow a weekly programme grid from this file instead of playing -dir")
+	archiveDir  = flag.String("archive", "", "Record what goes out into hourly Ogg Opus files in this directory")
+	archiveKeep = flag.Duration("archive-keep", 0, "Delete archive files older than this, e.g. 720h (default: keep)")
 )
 
-type Playlist struct {
-	files   []string
-	current int
-}
-
 func main() {
 	flag.Parse()
 
+	if *schedFile != "" {
+		runSchedule(*schedFile)
+		return
+	}
+
 	// Determine music directory
 	dir := *musicDir
 	if dir == "" {
@@ -67,31 +74,31 @@ func main() {
 
 	// Scan for MP3 files
 	fmt.Println("🔍 Scanning for MP3 files...")
-	playlist, err := scanMusicDir(dir)
+	list, err := playlist.Scan(dir)
 	if err != nil {
 		fmt.Printf("Error scanning directory: %v\n", err)
 		os.Exit(1)
 	}
 
-	if len(playlist.files) == 0 {
+	if list.Len() == 0 {
 		fmt.Println("No MP3 files found!")
 		fmt.Printf("Checked directory: %s\n", dir)
 		os.Exit(1)
 	}
 
-	fmt.Printf("✅ Found %d MP3 file(s)\n", len(playlist.files))
+	fmt.Printf("✅ Found %d MP3 file(s)\n", list.Len())
 	fmt.Println()
 
 	// Show playlist preview
 	fmt.Println("📻 Playlist:")
-	for i, file := range playlist.files {
+	for i, file := range list.Files() {
 		name := filepath.Base(file)
 		if i < 10 {
 			fmt.Printf("  %d. %s\n", i+1, name)
 		}
 	}
-	if len(playlist.files) > 10 {
-		fmt.Printf("  ... and %d more\n", len(playlist.files)-10)
+	if list.Len() > 10 {
+		fmt.Printf("  ... and %d more\n", list.Len()-10)
 	}
 	fmt.Println()
 
@@ -116,145 +123,135 @@ func main() {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
-	// Create shared subscription manager (persists across songs)
-	subManager := multicast.NewSubscriptionManager()
-
-	// Play through playlist
-	for {
-		for i, file := range playlist.files {
-			// Check for interrupt
-			select {
-			case <-sigChan:
-				fmt.Println("\n\nStopping music broadcast...")
-				return
-			default:
-			}
-
-			fmt.Printf("▶️  Now playing [%d/%d]: %s\n", i+1, len(playlist.files), filepath.Base(file))
-
-			// Get file info for duration display
-			duration, sampleRate := getMP3Info(file)
-			fmt.Printf("   Duration: %s | Sample Rate: %d Hz\n", duration.Round(time.Second), sampleRate)
-
-			// Broadcast this file (pass shared subManager)
-			if err := broadcastFile(file, ipv6, *port, *group, *callsign, subManager, sigChan); err != nil {
-				if err == io.EOF {
-					// File finished normally
-					fmt.Printf("   ✅ Completed\n\n")
-				} else {
-					fmt.Printf("   ❌ Error: %v\n\n", err)
-					// If interrupted, exit main loop
-					if err.Error() == "interrupted" {
-						fmt.Println("\n✅ Stopped by user")
-						return
-					}
-				}
-			}
-		}
-
-		// Check if we should loop
-		if !*loop {
-			break
+	// Load group keys for private groups
+	var keyring *encryption.Keyring
+	if *keysFile != "" {
+		keyring, err = encryption.LoadKeyring(*keysFile)
+		if err != nil {
+			fmt.Printf("Error loading keys: %v\n", err)
+			os.Exit(1)
 		}
-
-		fmt.Println("🔄 Looping playlist...\n")
 	}
 
-	fmt.Println("✅ Playlist complete!")
-}
-
-func broadcastFile(filepath string, ipv6 net.IP, port int, group, callsign string, subMgr *multicast.SubscriptionManager, sigChan chan os.Signal) error {
-	// Create audio config for music - use high quality settings
+	// Music quality settings, shared by every track
 	audioConfig := audio.DefaultConfig()
 
-	// Create FFmpeg source (more reliable than go-mp3)
-	ffmpegSource, err := audio.NewFFmpegSource(filepath, audioConfig)
+	current, err := list.OpenNext(audioConfig, *callsign, *loop)
 	if err != nil {
-		return fmt.Errorf("failed to create FFmpeg source: %w", err)
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
 	}
 
-	// Create broadcaster with FFmpeg source and shared subscription manager
-	cfg := broadcaster.Config{
-		Callsign:        callsign,
-		IPv6:            ipv6,
-		Port:            port,
-		Group:           group,
-		AudioConfig:     audioConfig,
-		AudioSource:     ffmpegSource,   // Use FFmpeg as audio source!
-		SubscriptionMgr: subMgr,          // Share subscription manager across songs!
+	// With talk-over the tracks play through a mixer, under the microphone
+	var source audio.AudioSource = current.Source
+	var mixer *audio.Mixer
+	if *talkover {
+		mixer = audio.NewMixer(audio.MixerConfig{
+			Stream:  audioConfig,
+			Ducking: &audio.DuckingConfig{Depth: *duckDB},
+		})
+		if err := mixer.AddInput(audio.MixerInput{Name: "music", Source: current.Source, Bed: true}); err != nil {
+			fmt.Printf("Error adding music: %v\n", err)
+			os.Exit(1)
+		}
+		if err := mixer.AddInput(audio.MixerInput{Name: "mic", Source: audio.NewMicrophoneSource(audioConfig), Voice: true}); err != nil {
+			fmt.Printf("Error adding microphone: %v\n", err)
+			os.Exit(1)
+		}
+		source = mixer
+		fmt.Printf("🎙️  Talk-over on: music ducks %.0f dB while you talk\n", *duckDB)
 	}
 
-	b, err := broadcaster.New(cfg)
+	// One broadcaster for the whole session: tracks are swapped into it, so
+	// transport, Opus encoder and sequence numbers run on without a gap
+	b, err := broadcaster.New(broadcaster.Config{
+		Callsign:    *callsign,
+		IPv6:        ipv6,
+		Port:        *port,
+		Group:       *group,
+		AudioConfig: audioConfig,
+		AudioSource: source,
+		Keyring:     keyring,
+		Archive:     archiveConfig(),
+		DTX:         new(bool), // Music: never gate quiet passages
+	})
 	if err != nil {
-		return fmt.Errorf("failed to create broadcaster: %w", err)
+		fmt.Printf("Error creating broadcaster: %v\n", err)
+		os.Exit(1)
 	}
 
-	// Start broadcasting
 	if err := b.Start(); err != nil {
-		return fmt.Errorf("failed to start broadcaster: %w", err)
+		fmt.Printf("Error starting broadcaster: %v\n", err)
+		os.Exit(1)
 	}
-
-	// Wait for file to finish or interrupt
-	// The broadcaster will automatically stop when FFmpeg source returns EOF
-	// We'll check periodically for signals
-	for {
-		select {
-		case <-sigChan:
-			b.Stop()
-			return fmt.Errorf("interrupted")
-		case <-time.After(1 * time.Second):
-			// Check if source is still running
-			if !ffmpegSource.IsRunning() {
-				b.Stop()
-				return io.EOF
-			}
+	defer b.Stop()
+
+	// Tracks change on the broadcaster, or on the mixer's music input
+	trackEnded := b.SourceEnded()
+	playNext := b.SetSource
+	if mixer != nil {
+		trackEnded = inputEnded(mixer, "music")
+		playNext = func(src audio.AudioSource) error {
+			return mixer.ReplaceSource("music", src)
 		}
 	}
-}
 
-func scanMusicDir(dir string) (*Playlist, error) {
-	var files []string
+	for {
+		current.Announce(list.Len())
+		b.SetMetadata(current.Metadata)
 
-	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
+		// Prepare the next track while this one plays
+		next, err := list.OpenNext(audioConfig, *callsign, *loop)
+		if err != nil && err != io.EOF {
+			fmt.Printf("   ❌ Error: %v\n", err)
 		}
 
-		if !info.IsDir() {
-			ext := strings.ToLower(filepath.Ext(path))
-			if ext == ".mp3" {
-				files = append(files, path)
+		select {
+		case <-sigChan:
+			fmt.Println("\n\nStopping music broadcast...")
+			if next != nil {
+				next.Source.Stop()
 			}
+			fmt.Println("\n✅ Stopped by user")
+			return
+		case <-trackEnded:
+			fmt.Printf("   ✅ Completed\n\n")
 		}
 
-		return nil
-	})
-
-	if err != nil {
-		return nil, err
+		if next == nil {
+			break
+		}
+		if err := playNext(next.Source); err != nil {
+			fmt.Printf("Error switching track: %v\n", err)
+			return
+		}
+		current = next
 	}
 
-	return &Playlist{
-		files:   files,
-		current: 0,
-	}, nil
+	fmt.Println("✅ Playlist complete!")
+	b.StopWith(protocol.OffAirSourceEnded, time.Time{})
 }
 
-func getMP3Info(filepath string) (time.Duration, int) {
-	f, err := os.Open(filepath)
-	if err != nil {
-		return 0, 0
-	}
-	defer f.Close()
+// inputEnded signals each time the named mixer input runs out
+func inputEnded(mixer *audio.Mixer, name string) <-chan struct{} {
+	ended := make(chan struct{}, 1)
+	go func() {
+		for n := range mixer.InputEnded() {
+			if n == name {
+				ended <- struct{}{}
+			}
+		}
+	}()
+	return ended
+}
 
-	decoder, err := mp3.NewDecoder(f)
-	if err != nil {
-		return 0, 0
+// archiveConfig returns the archive policy from the flags (nil = not archiving)
+func archiveConfig() *archive.Config {
+	if *archiveDir == "" {
+		return nil
+	}
+	return &archive.Config{
+		Dir:       *archiveDir,
+		Retention: *archiveKeep,
 	}
-
-	sampleRate := decoder.SampleRate()
-	length := decoder.Length()
-	duration := time.Duration(length) * time.Second / time.Duration(sampleRate) / 4 // 4 = 2 channels * 2 bytes per sample
-
-	return duration, sampleRate
 }
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/meshradio/meshradio/internal/broadcaster"
+	"github.com/meshradio/meshradio/internal/scheduler"
+	"github.com/meshradio/meshradio/pkg/audio"
+	"github.com/meshradio/meshradio/pkg/encryption"
+	"github.com/meshradio/meshradio/pkg/schedule"
+	"github.com/meshradio/meshradio/pkg/yggdrasil"
+)
+
+// runSchedule broadcasts the programme grid of a schedule file
+func runSchedule(path string) {
+	sched, err := schedule.Load(path)
+	if err != nil {
+		fmt.Printf("Error loading schedule: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("╔══════════════════════════════════════════════════════════════╗\n")
+	fmt.Printf("║ MeshRadio Scheduled Broadcaster                              ║\n")
+	fmt.Printf("╠══════════════════════════════════════════════════════════════╣\n")
+	fmt.Printf("║ Callsign:    %-47s ║\n", *callsign)
+	fmt.Printf("║ Channel:     %-47s ║\n", *group)
+	fmt.Printf("║ Port:        %-47d ║\n", *port)
+	fmt.Printf("║ Schedule:    %-47s ║\n", fmt.Sprintf("%s (%d slots)", path, len(sched.Slots)))
+	fmt.Printf("╚══════════════════════════════════════════════════════════════╝\n")
+	fmt.Println()
+
+	// Get local IPv6
+	ipv6, err := yggdrasil.GetLocalIPv6()
+	if err != nil {
+		fmt.Printf("Warning: Could not get Yggdrasil IPv6: %v\n", err)
+		fmt.Println("Using localhost for testing")
+		ipv6 = net.IPv6loopback
+	}
+
+	// Load group keys for private groups
+	var keyring *encryption.Keyring
+	if *keysFile != "" {
+		keyring, err = encryption.LoadKeyring(*keysFile)
+		if err != nil {
+			fmt.Printf("Error loading keys: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	audioConfig := audio.DefaultConfig()
+
+	// The scheduler swaps the first programme in right after start
+	b, err := broadcaster.New(broadcaster.Config{
+		Callsign:    *callsign,
+		IPv6:        ipv6,
+		Port:        *port,
+		Group:       *group,
+		AudioConfig: audioConfig,
+		AudioSource: audio.NewSilenceSource(audioConfig),
+		Keyring:     keyring,
+		Archive:     archiveConfig(),
+		DTX:         new(bool), // Music: never gate quiet passages
+	})
+	if err != nil {
+		fmt.Printf("Error creating broadcaster: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := b.Start(); err != nil {
+		fmt.Printf("Error starting broadcaster: %v\n", err)
+		os.Exit(1)
+	}
+	defer b.Stop()
+
+	s, err := scheduler.New(scheduler.Config{
+		Broadcaster: b,
+		Schedule:    sched,
+		AudioConfig: audioConfig,
+	})
+	if err != nil {
+		fmt.Printf("Error creating scheduler: %v\n", err)
+		os.Exit(1)
+	}
+	if err := s.Start(); err != nil {
+		fmt.Printf("Error starting scheduler: %v\n", err)
+		os.Exit(1)
+	}
+	defer s.Stop()
+
+	fmt.Printf("📡 Broadcasting on: %s:%d\n", ipv6.String(), *port)
+	fmt.Println()
+	fmt.Println("Commands (type and press Enter):")
+	fmt.Println("  live [MIN]      - Go live on the microphone (for MIN minutes, default: until resume)")
+	fmt.Println("  silence [MIN]   - Go silent")
+	fmt.Println("  play DIR        - Play a music directory until resume")
+	fmt.Println("  resume          - Back to the schedule")
+	fmt.Println("  now             - Show what is on air")
+	fmt.Println("  Ctrl+C          - Stop broadcasting (kill -HUP reloads the schedule file)")
+	fmt.Println()
+
+	commands := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			commands <- strings.TrimSpace(scanner.Text())
+		}
+	}()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+
+	for {
+		select {
+		case sig := <-sigChan:
+			if sig != syscall.SIGHUP {
+				fmt.Println("\n\nStopping scheduled broadcast...")
+				return
+			}
+			reloaded, err := schedule.Load(path)
+			if err != nil {
+				fmt.Printf("⚠️  Keeping old schedule: %v\n", err)
+				continue
+			}
+			s.SetSchedule(reloaded)
+			fmt.Printf("🔄 Reloaded schedule (%d slots)\n", len(reloaded.Slots))
+
+		case cmd := <-commands:
+			if err := scheduleCommand(s, cmd); err != nil {
+				fmt.Printf("⚠️  %v\n", err)
+			}
+		}
+	}
+}
+
+// scheduleCommand applies one operator command to the scheduler
+func scheduleCommand(s *scheduler.Scheduler, cmd string) error {
+	name, arg, _ := strings.Cut(cmd, " ")
+	arg = strings.TrimSpace(arg)
+
+	switch name {
+	case "":
+		return nil
+
+	case "live", "silence":
+		slot := schedule.Slot{Kind: schedule.KindLive, Source: "mic", Title: "Live"}
+		if name == "silence" {
+			slot = schedule.Slot{Kind: schedule.KindSilence}
+		}
+		var until time.Time
+		if arg != "" {
+			minutes, err := strconv.Atoi(arg)
+			if err != nil || minutes <= 0 {
+				return fmt.Errorf("invalid minutes %q", arg)
+			}
+			until = time.Now().Add(time.Duration(minutes) * time.Minute)
+		}
+		s.Override(slot, until)
+
+	case "play":
+		if arg == "" {
+			return fmt.Errorf("usage: play DIR")
+		}
+		s.Override(schedule.Slot{Kind: schedule.KindPlaylist, Source: arg}, time.Time{})
+
+	case "resume":
+		s.ClearOverride()
+
+	case "now":
+		if slot, ok := s.Current(); ok {
+			fmt.Printf("📅 On air: %s\n", &slot)
+		} else {
+			fmt.Println("📅 Nothing scheduled")
+		}
+
+	default:
+		return fmt.Errorf("unknown command %q", name)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/meshradio/meshradio/internal/relay"
+	"github.com/meshradio/meshradio/pkg/multicast"
+	"github.com/meshradio/meshradio/pkg/network"
+	"github.com/meshradio/meshradio/pkg/signing"
+	"github.com/meshradio/meshradio/pkg/yggdrasil"
+)
+
+func main() {
+	callsign := flag.String("callsign", "RELAY", "Callsign of this relay")
+	target := flag.String("target", "", "Upstream station IPv6 address")
+	targetPort := flag.Int("target-port", 8799, "Upstream station port")
+	port := flag.Int("port", 8799, "Port listeners subscribe to")
+	upstreamPort := flag.Int("upstream-port", 0, "Local port for the upstream subscription (default: port+1000)")
+	group := flag.String("group", "default", "Group to relay")
+	maxListeners := flag.Int("max-listeners", 0, "Maximum downstream listeners (0 = unlimited)")
+	keyFile := flag.String("key", "", "Key file for signing the relay's own beacons and upstream subscriptions (created if missing)")
+	multicastIf := flag.String("multicast", "", "Also send natively to the IPv6 multicast group on this interface (\"any\" = system choice)")
+	allow := flag.String("allow", "", "Only admit these listeners: comma list of callsigns, IPv6 addresses, prefixes or key:HEX")
+	deny := flag.String("deny", "", "Refuse these listeners: comma list of callsigns, IPv6 addresses, prefixes or key:HEX")
+	trustFile := flag.String("trust", "", "Listener keys (\"CALLSIGN PUBLICKEY\" per line); callsign rules match only listeners signing with one")
+	flag.Parse()
+
+	upstreamIPv6 := net.ParseIP(*target)
+	if upstreamIPv6 == nil {
+		fmt.Println("Usage: relay -target <upstream-ipv6> [-target-port 8799] [-port 8799] [-group default]")
+		os.Exit(1)
+	}
+
+	var signingKey ed25519.PrivateKey
+	if *keyFile != "" {
+		key, err := signing.LoadOrCreateKey(*keyFile)
+		if err != nil {
+			fmt.Printf("Error loading signing key: %v\n", err)
+			os.Exit(1)
+		}
+		signingKey = key
+	}
+
+	var trustStore *signing.TrustStore
+	if *trustFile != "" {
+		ts, err := signing.LoadTrustStore(*trustFile)
+		if err != nil {
+			fmt.Printf("Error loading trust store: %v\n", err)
+			os.Exit(1)
+		}
+		trustStore = ts
+	}
+
+	var mcast *network.MulticastConfig
+	if *multicastIf != "" {
+		mcast = &network.MulticastConfig{}
+		if *multicastIf != "any" {
+			ifi, err := net.InterfaceByName(*multicastIf)
+			if err != nil {
+				fmt.Printf("Error finding interface %s: %v\n", *multicastIf, err)
+				os.Exit(1)
+			}
+			mcast.Interface = ifi
+		}
+	}
+
+	// Admission policy for downstream listeners
+	allowRules, err := multicast.ParseRules(*allow)
+	if err != nil {
+		fmt.Printf("Invalid -allow: %v\n", err)
+		os.Exit(1)
+	}
+	denyRules, err := multicast.ParseRules(*deny)
+	if err != nil {
+		fmt.Printf("Invalid -deny: %v\n", err)
+		os.Exit(1)
+	}
+	subManager := multicast.NewSubscriptionManager()
+	subManager.SetPolicy(multicast.AllGroups, multicast.Policy{Allow: allowRules, Deny: denyRules})
+
+	// Get local IPv6
+	ipv6, err := yggdrasil.GetLocalIPv6()
+	if err != nil {
+		fmt.Printf("Error getting IPv6: %v\n", err)
+		fmt.Println("Make sure Yggdrasil is running!")
+		os.Exit(1)
+	}
+
+	fmt.Printf("╔══════════════════════════════════════════════════════════════╗\n")
+	fmt.Printf("║ MeshRadio Relay                                              ║\n")
+	fmt.Printf("╠══════════════════════════════════════════════════════════════╣\n")
+	fmt.Printf("║ Upstream:    %-47s ║\n", fmt.Sprintf("[%s]:%d", upstreamIPv6, *targetPort))
+	fmt.Printf("║ Group:       %-47s ║\n", *group)
+	fmt.Printf("║ Port:        %-47d ║\n", *port)
+	fmt.Printf("║ Callsign:    %-47s ║\n", *callsign)
+	fmt.Printf("║ IPv6:        %-47s ║\n", ipv6.String())
+	fmt.Printf("╚══════════════════════════════════════════════════════════════╝\n")
+	fmt.Println()
+
+	r, err := relay.New(relay.Config{
+		Callsign:          *callsign,
+		IPv6:              ipv6,
+		Port:              *port,
+		Group:             *group,
+		UpstreamIPv6:      upstreamIPv6,
+		UpstreamPort:      *targetPort,
+		UpstreamLocalPort: *upstreamPort,
+		MaxListeners:      *maxListeners,
+		SigningKey:        signingKey,
+		TrustStore:        trustStore,
+		SubscriptionMgr:   subManager,
+		Multicast:         mcast,
+	})
+	if err != nil {
+		fmt.Printf("Error creating relay: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := r.Start(); err != nil {
+		fmt.Printf("Error starting relay: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("Relaying. Press Ctrl+C to stop.")
+	fmt.Println()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			stats := r.Stats()
+			fmt.Printf("📊 Listeners: %d | Forwarded: %d | Loop drops: %d | Errors: %d\n",
+				r.Downstream().GetListenerCount(), stats.Forwarded, stats.HopDrops, stats.Errors)
+		case <-sigChan:
+			fmt.Println("\nStopping relay...")
+			r.Stop()
+			return
+		}
+	}
+}
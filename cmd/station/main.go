@@ -0,0 +1,286 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/meshradio/meshradio/internal/broadcaster"
+	"github.com/meshradio/meshradio/pkg/archive"
+	"github.com/meshradio/meshradio/pkg/audio"
+	"github.com/meshradio/meshradio/pkg/encryption"
+	"github.com/meshradio/meshradio/pkg/multicast"
+	"github.com/meshradio/meshradio/pkg/signing"
+	"github.com/meshradio/meshradio/pkg/yggdrasil"
+)
+
+// streamFlags collects repeated -stream arguments
+type streamFlags []string
+
+func (s *streamFlags) String() string     { return strings.Join(*s, ",") }
+func (s *streamFlags) Set(v string) error { *s = append(*s, v); return nil }
+
+func main() {
+	var streams streamFlags
+	flag.Var(&streams, "stream", "Stream as GROUP=FILE or GROUP=mic (repeat for more streams)")
+	callsign := flag.String("callsign", "STATION", "Your callsign")
+	port := flag.Int("port", 8799, "Port shared by all streams")
+	keyFile := flag.String("key", "", "Station key file for signing (created if missing)")
+	keysFile := flag.String("keys", "", "Group keys file for private groups (\"GROUP KEYID KEY\" per line)")
+	archiveDir := flag.String("archive", "", "Record every stream into hourly Ogg Opus files in this directory")
+	archiveKeep := flag.Duration("archive-keep", 0, "Delete archive files older than this, e.g. 720h (default: keep)")
+	maxListeners := flag.Int("max-listeners", 0, "Maximum listeners per stream (0 = unlimited)")
+	allow := flag.String("allow", "", "Only admit these listeners: comma list of callsigns, IPv6 addresses, prefixes or key:HEX")
+	deny := flag.String("deny", "", "Refuse these listeners: comma list of callsigns, IPv6 addresses, prefixes or key:HEX")
+	trustFile := flag.String("trust", "", "Listener keys (\"CALLSIGN PUBLICKEY\" per line); callsign rules match only listeners signing with one")
+	flag.Parse()
+
+	if len(streams) == 0 {
+		fmt.Println("Usage: station -stream music=song.mp3 -stream talk=mic [-port 8799]")
+		os.Exit(1)
+	}
+
+	// Admission policy for every stream
+	allowRules, err := multicast.ParseRules(*allow)
+	if err != nil {
+		fmt.Printf("Invalid -allow: %v\n", err)
+		os.Exit(1)
+	}
+	denyRules, err := multicast.ParseRules(*deny)
+	if err != nil {
+		fmt.Printf("Invalid -deny: %v\n", err)
+		os.Exit(1)
+	}
+	subManager := multicast.NewSubscriptionManager()
+	subManager.SetPolicy(multicast.AllGroups, multicast.Policy{
+		MaxSubscribers: *maxListeners,
+		Allow:          allowRules,
+		Deny:           denyRules,
+	})
+
+	cfg := broadcaster.HostConfig{
+		Callsign:        *callsign,
+		Port:            *port,
+		SubscriptionMgr: subManager,
+	}
+
+	if *keyFile != "" {
+		key, err := signing.LoadOrCreateKey(*keyFile)
+		if err != nil {
+			fmt.Printf("Error loading signing key: %v\n", err)
+			os.Exit(1)
+		}
+		cfg.SigningKey = key
+	}
+	if *trustFile != "" {
+		trustStore, err := signing.LoadTrustStore(*trustFile)
+		if err != nil {
+			fmt.Printf("Error loading trust store: %v\n", err)
+			os.Exit(1)
+		}
+		cfg.TrustStore = trustStore
+	}
+	if *keysFile != "" {
+		keyring, err := encryption.LoadKeyring(*keysFile)
+		if err != nil {
+			fmt.Printf("Error loading keys: %v\n", err)
+			os.Exit(1)
+		}
+		cfg.Keyring = keyring
+	}
+
+	// Get local IPv6
+	ipv6, err := yggdrasil.GetLocalIPv6()
+	if err != nil {
+		fmt.Printf("Error getting IPv6: %v\n", err)
+		fmt.Println("Make sure Yggdrasil is running!")
+		os.Exit(1)
+	}
+	cfg.IPv6 = ipv6
+
+	host, err := broadcaster.NewHost(cfg)
+	if err != nil {
+		fmt.Printf("Error creating station host: %v\n", err)
+		os.Exit(1)
+	}
+
+	audioConfig := audio.DefaultConfig()
+	for _, s := range streams {
+		group, input, ok := strings.Cut(s, "=")
+		if !ok || group == "" || input == "" {
+			fmt.Printf("Invalid stream %q, expected GROUP=FILE or GROUP=mic\n", s)
+			os.Exit(1)
+		}
+
+		streamCfg := broadcaster.StreamConfig{
+			Group:       group,
+			AudioConfig: audioConfig,
+		}
+		if *archiveDir != "" {
+			streamCfg.Archive = &archive.Config{Dir: *archiveDir, Retention: *archiveKeep}
+		}
+		if input != "mic" {
+			src, err := audio.NewFFmpegSource(input, audioConfig)
+			if err != nil {
+				fmt.Printf("Error opening %s: %v\n", input, err)
+				os.Exit(1)
+			}
+			streamCfg.AudioSource = src
+			streamCfg.DTX = new(bool) // Programme audio, not a voice net
+		}
+
+		b, err := host.AddStream(streamCfg)
+		if err != nil {
+			fmt.Printf("Error adding stream: %v\n", err)
+			os.Exit(1)
+		}
+		if input != "mic" {
+			go loopFile(b, input, audioConfig)
+		}
+		fmt.Printf("  %-12s ← %s\n", group, input)
+	}
+
+	if err := host.Start(); err != nil {
+		fmt.Printf("Error starting station host: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Broadcasting %d stream(s) on [%s]:%d. Press Ctrl+C to stop.\n", len(streams), ipv6, *port)
+	fmt.Println()
+	fmt.Println("Commands (type and press Enter):")
+	fmt.Println("  listeners                    - Show who is listening")
+	fmt.Println("  kick GROUP IPV6 PORT [MIN]   - Drop a listener (and ban its address for MIN minutes)")
+	fmt.Println("  ban GROUP|* RULE [MIN]       - Ban a callsign, IPv6 address, prefix or key:HEX (default: until unban)")
+	fmt.Println("  unban GROUP|* RULE           - Lift a ban")
+	fmt.Println()
+
+	commands := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			commands <- strings.TrimSpace(scanner.Text())
+		}
+	}()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+
+	for {
+		select {
+		case cmd := <-commands:
+			if err := stationCommand(host, subManager, cmd); err != nil {
+				fmt.Printf("⚠️  %v\n", err)
+			}
+		case <-sigChan:
+			fmt.Println("\nStopping station host...")
+			host.Stop()
+			return
+		}
+	}
+}
+
+// stationCommand applies one operator command to the station
+func stationCommand(host *broadcaster.Host, sm *multicast.SubscriptionManager, cmd string) error {
+	fields := strings.Fields(cmd)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	// Optional trailing duration in minutes
+	minutes := func(args []string) (time.Duration, error) {
+		if len(args) == 0 {
+			return 0, nil
+		}
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid minutes %q", args[0])
+		}
+		return time.Duration(n) * time.Minute, nil
+	}
+
+	switch fields[0] {
+	case "listeners":
+		for _, b := range host.Streams() {
+			group := b.GetGroup()
+			for _, sub := range sm.GetSubscribers(group) {
+				fmt.Printf("  %-12s %-10s %s %d\n", group, sub.Callsign, sub.IPv6, sub.Port)
+			}
+		}
+
+	case "kick":
+		if len(fields) < 4 {
+			return fmt.Errorf("usage: kick GROUP IPV6 PORT [MIN]")
+		}
+		stream, ok := host.Stream(fields[1])
+		if !ok {
+			return fmt.Errorf("no stream for group '%s'", fields[1])
+		}
+		ip := net.ParseIP(fields[2])
+		port, err := strconv.Atoi(fields[3])
+		if ip == nil || err != nil {
+			return fmt.Errorf("invalid listener address %s %s", fields[2], fields[3])
+		}
+		ban, err := minutes(fields[4:])
+		if err != nil {
+			return err
+		}
+		return stream.Kick(ip, port, ban)
+
+	case "ban":
+		if len(fields) < 3 {
+			return fmt.Errorf("usage: ban GROUP|* RULE [MIN]")
+		}
+		rule, err := multicast.ParseRule(fields[2])
+		if err != nil {
+			return err
+		}
+		d, err := minutes(fields[3:])
+		if err != nil {
+			return err
+		}
+		var until time.Time
+		if d > 0 {
+			until = time.Now().Add(d)
+		}
+		host.Ban(fields[1], rule, until, "operator")
+
+	case "unban":
+		if len(fields) < 3 {
+			return fmt.Errorf("usage: unban GROUP|* RULE")
+		}
+		rule, err := multicast.ParseRule(fields[2])
+		if err != nil {
+			return err
+		}
+		if !sm.Unban(fields[1], rule) {
+			return fmt.Errorf("no ban for %s in '%s'", rule, fields[1])
+		}
+		fmt.Printf("✅ Lifted ban on %s\n", rule)
+
+	default:
+		return fmt.Errorf("unknown command %q", fields[0])
+	}
+	return nil
+}
+
+// loopFile restarts a file stream each time it runs out
+func loopFile(b *broadcaster.Broadcaster, path string, config audio.StreamConfig) {
+	for range b.SourceEnded() {
+		src, err := audio.NewFFmpegSource(path, config)
+		if err != nil {
+			fmt.Printf("Error reopening %s: %v\n", path, err)
+			return
+		}
+		if err := b.SetSource(src); err != nil {
+			fmt.Printf("Error restarting %s: %v\n", path, err)
+			return
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package broadcaster
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/meshradio/meshradio/pkg/multicast"
+	"github.com/meshradio/meshradio/pkg/protocol"
+)
+
+// SetPolicy sets the admission policy of this broadcaster's group
+// The policy lives in the subscription manager, so every broadcaster sharing
+// it (a station host, songs of a playlist) enforces it.
+func (b *Broadcaster) SetPolicy(policy multicast.Policy) {
+	b.subManager.SetPolicy(b.group, policy)
+}
+
+// Kick ends a listener's subscription and tells the listener. With ban > 0
+// its address is also kept out of the group for that long.
+func (b *Broadcaster) Kick(ipv6 net.IP, port int, ban time.Duration) error {
+	sub, err := b.subManager.Kick(b.group, ipv6, port)
+	if err != nil {
+		return fmt.Errorf("failed to kick %s:%d: %w", ipv6, port, err)
+	}
+
+	result := protocol.RejectKicked
+	if ban > 0 {
+		b.subManager.Ban(b.group, multicast.AddressRule(ipv6), time.Now().Add(ban), "kicked")
+		result = protocol.RejectBanned
+	}
+
+	b.endSubscription(b.group, sub, result)
+	fmt.Printf("👢 Kicked %s [%s]:%d from group '%s'\n", sub.Callsign, ipv6, port, b.group)
+	return nil
+}
+
+// Ban keeps listeners matching a rule out of this broadcaster's group until
+// the given time (zero = until Unban). Current subscribers that match are dropped.
+func (b *Broadcaster) Ban(rule multicast.Rule, until time.Time, reason string) {
+	b.endSubscriptions(b.subManager.Ban(b.group, rule, until, reason))
+	fmt.Printf("🚫 Banned %s from group '%s'\n", rule, b.group)
+}
+
+// Unban lifts a ban from this broadcaster's group
+func (b *Broadcaster) Unban(rule multicast.Rule) bool {
+	return b.subManager.Unban(b.group, rule)
+}
+
+// Ban keeps listeners matching a rule out of a group of the host
+// (multicast.AllGroups = every group). Current subscribers that match are dropped.
+func (h *Host) Ban(group string, rule multicast.Rule, until time.Time, reason string) {
+	removed := h.subManager.Ban(group, rule, until, reason)
+	for _, r := range removed {
+		if stream, ok := h.Stream(r.Group); ok {
+			stream.endSubscription(r.Group, r.Subscriber, protocol.RejectBanned)
+		}
+	}
+	fmt.Printf("🚫 Banned %s from group '%s'\n", rule, group)
+}
+
+// endSubscriptions tells the listeners removed by a ban
+func (b *Broadcaster) endSubscriptions(removed []multicast.Removal) {
+	for _, r := range removed {
+		if stream := b.streamFor(r.Group); stream != nil {
+			stream.endSubscription(r.Group, r.Subscriber, protocol.RejectBanned)
+		}
+	}
+}
+
+// endSubscription tells a removed listener why its subscription ended
+func (b *Broadcaster) endSubscription(group string, sub *multicast.Subscriber, result uint8) {
+	b.forgetListener(sub.IPv6, uint16(sub.Port))
+	b.sendSubscribeAck(sub.IPv6, sub.Port, group, result)
+}
@@ -1,18 +1,41 @@
 package broadcaster
 
 import (
+	"crypto/ed25519"
+	cryptorand "crypto/rand"
+	"errors"
 	"fmt"
+	"io"
+	"math/rand"
 	"net"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/meshradio/meshradio/pkg/archive"
 	"github.com/meshradio/meshradio/pkg/audio"
+	"github.com/meshradio/meshradio/pkg/discovery"
 	"github.com/meshradio/meshradio/pkg/emergency"
+	"github.com/meshradio/meshradio/pkg/encryption"
 	"github.com/meshradio/meshradio/pkg/multicast"
 	"github.com/meshradio/meshradio/pkg/network"
 	"github.com/meshradio/meshradio/pkg/protocol"
+	"github.com/meshradio/meshradio/pkg/signing"
 )
 
+// Default subscription lease offered to listeners in SUBSCRIBE-ACK
+const (
+	DefaultHeartbeatInterval = 5 * time.Second
+	DefaultLeaseTimeout      = 15 * time.Second
+)
+
+// DefaultBeaconInterval is how often station beacons are sent to subscribers
+const DefaultBeaconInterval = 5 * time.Second
+
+// metadataInterval is how often now-playing metadata is repeated so late
+// joiners and lossy links catch up
+const metadataInterval = 10 * time.Second
+
 // ListenerConn represents a connected listener
 type ListenerConn struct {
 	IPv6        net.IP
@@ -29,21 +52,80 @@ type Broadcaster struct {
 	port        int
 	group       string  // Multicast group name (e.g., "emergency", "community")
 	priority    uint8   // Broadcast priority (0-3)
-	transport   *network.Transport
-	audioSource audio.AudioSource // Can be microphone, MP3 file, etc.
+	transport   network.Transport
+	audioSource audio.AudioSource // Can be microphone, MP3 file, etc. (guarded by sourceMu, nil for a relay)
 	codec       audio.Codec
+	relay       bool  // Stream packets come in through Forward instead of being encoded here
+	host        *Host // Station host sharing its transport (nil = own transport)
 	config      audio.StreamConfig
 	running     bool
 	mu          sync.Mutex
-	seqNum      uint8
+	seqNum      uint32
+	mediaTime   uint32 // Media timestamp of the next frame, in samples
+	streamID    uint32 // Random per broadcaster (SSRC)
 	stopChan    chan struct{}
 
+	// Audio source swapping (see SetSource)
+	sourceMu    sync.Mutex
+	endedSource audio.AudioSource // Source that already signalled sourceEnded
+	sourceEnded chan struct{}
+
+	// Subscription lease (negotiated with listeners via SUBSCRIBE-ACK)
+	heartbeatInterval time.Duration
+	leaseTimeout      time.Duration
+
+	// Secret for stateless SUBSCRIBE challenge cookies
+	cookieSecret []byte
+
+	// Station beacons
+	beaconInterval time.Duration
+	startedAt      time.Time
+
+	// Now-playing metadata (nil = none set)
+	metadata      *protocol.Metadata
+	metadataSetAt time.Time
+	metadataMu    sync.Mutex
+
 	// Subscription manager (Layer 4: Multicast Overlay)
 	subManager *multicast.SubscriptionManager
 
 	// Channel registry (Layer 5: Emergency)
 	channelRegistry *emergency.ChannelRegistry
 
+	// Station key for packet signatures (nil = unsigned)
+	signingKey ed25519.PrivateKey
+
+	// Listener keys: a SUBSCRIBE signed by a trusted key verifies its callsign (nil = none)
+	trustStore *signing.TrustStore
+
+	// Group keys for private channels (nil or no key for the group = clear)
+	keyring *encryption.Keyring
+
+	// Stations heard of through mesh discovery (optional, re-advertised to peers)
+	discoveryCache *discovery.Cache
+
+	// Signal reports and failed sends per subscriber, key: "ipv6:port"
+	reports    map[string]*ListenerReport
+	sendErrors map[string]uint64
+	reportsMu  sync.Mutex
+
+	// Native IPv6 multicast group for audio (nil = unicast fan-out only)
+	mcastGroup net.IP
+	mcastPort  int
+
+	// Ogg Opus archive of the frames sent (nil = not archiving)
+	archive       *archive.Recorder
+	archiveErrors uint64 // Failed frame writes (broadcastLoop only)
+
+	// Discontinuous transmission (nil = send every frame)
+	vad          *audio.VAD
+	silentFrames int // Frames since speech stopped (broadcastLoop only)
+
+	// Fan-out buffers reused across frames
+	fanoutMu      sync.Mutex
+	fanoutAddrs   []net.UDPAddr
+	fanoutTargets []*net.UDPAddr
+
 	// Legacy listener tracking (deprecated - use subManager instead)
 	listeners    map[string]*ListenerConn // key: "ipv6:port"
 	listenersMux sync.RWMutex
@@ -58,32 +140,56 @@ type Config struct {
 	AudioConfig       audio.StreamConfig
 	AudioSource       audio.AudioSource             // Optional: custom audio source (microphone, MP3, etc.). If nil, uses microphone.
 	SubscriptionMgr   *multicast.SubscriptionManager // Optional: shared subscription manager. If nil, creates new one.
+	HeartbeatInterval time.Duration                 // Optional: heartbeat interval offered to listeners (default: 5s)
+	LeaseTimeout      time.Duration                 // Optional: prune listeners silent for this long (default: 15s)
+	MaxListeners      int                           // Optional: reject subscriptions beyond this count on top of the group policy (0 = unlimited)
+	BeaconInterval    time.Duration                 // Optional: station beacon interval (default: 5s)
+	DiscoveryCache    *discovery.Cache              // Optional: stations heard of, included in discovery responses
+	SigningKey        ed25519.PrivateKey            // Optional: station key, signs every packet when set
+	TrustStore        *signing.TrustStore           // Optional: listener keys; callsign rules only match SUBSCRIBEs signed by one
+	Keyring           *encryption.Keyring           // Optional: encrypts audio and metadata if it holds a key for the group
+	Transport         network.Transport             // Optional: packet transport (default: UDP socket on Port)
+	Multicast         *network.MulticastConfig      // Optional: also send audio natively to the group's IPv6 multicast address
+	Relay             bool                          // Optional: relay mode, no audio source; packets are passed in with Forward
+	Priority          *emergency.Priority           // Optional: broadcast priority (default: the group's channel priority)
+	Archive           *archive.Config               // Optional: record the Opus frames sent into rolling Ogg Opus files
+	DTX               *bool                         // Optional: send only comfort noise keepalives during silence (default: on for voice channels)
+	VAD               audio.VADConfig               // Optional: voice activity detection tuning for DTX
 }
 
 // New creates a new broadcaster
 func New(cfg Config) (*Broadcaster, error) {
-	transport, err := network.NewTransport(cfg.Port)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create transport: %w", err)
+	transport := cfg.Transport
+	if transport == nil {
+		udp, err := network.NewTransport(cfg.Port)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create transport: %w", err)
+		}
+		transport = udp
 	}
 
-	// Use provided audio source, or default to microphone
+	// A relay passes on encoded packets and needs neither a source nor a codec
 	var audioSource audio.AudioSource
-	if cfg.AudioSource != nil {
-		audioSource = cfg.AudioSource
-	} else {
-		audioSource = audio.NewMicrophoneSource(cfg.AudioConfig)
-	}
+	var codec audio.Codec
+	if !cfg.Relay {
+		// Use provided audio source, or default to microphone
+		if cfg.AudioSource != nil {
+			audioSource = cfg.AudioSource
+		} else {
+			audioSource = audio.NewMicrophoneSource(cfg.AudioConfig)
+		}
 
-	// Create Opus codec for compression
-	codec, err := audio.NewOpusCodec(
-		cfg.AudioConfig.SampleRate,
-		cfg.AudioConfig.Channels,
-		cfg.AudioConfig.FrameSize,
-		cfg.AudioConfig.Bitrate,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Opus codec: %w", err)
+		// Create Opus codec for compression
+		opus, err := audio.NewOpusCodec(
+			cfg.AudioConfig.SampleRate,
+			cfg.AudioConfig.Channels,
+			cfg.AudioConfig.FrameSize,
+			cfg.AudioConfig.Bitrate,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Opus codec: %w", err)
+		}
+		codec = opus
 	}
 
 	// Default group if not specified
@@ -95,8 +201,22 @@ func New(cfg Config) (*Broadcaster, error) {
 	// Get priority for this channel/group
 	channelRegistry := emergency.NewChannelRegistry()
 	priority := uint8(emergency.PriorityNormal) // Default
+	dtx := false
 	if ch, ok := channelRegistry.GetByGroup(group); ok {
 		priority = uint8(ch.Priority)
+		dtx = ch.Voice
+	}
+	if cfg.Priority != nil {
+		priority = uint8(*cfg.Priority)
+	}
+	if cfg.DTX != nil {
+		dtx = *cfg.DTX
+	}
+
+	// Voice channels skip silence; a relay passes on whatever the origin sent
+	var vad *audio.VAD
+	if dtx && !cfg.Relay {
+		vad = audio.NewVAD(cfg.VAD, cfg.AudioConfig)
 	}
 
 	// Use provided subscription manager or create new one
@@ -105,20 +225,112 @@ func New(cfg Config) (*Broadcaster, error) {
 		subManager = multicast.NewSubscriptionManager()
 	}
 
+	// The listener cap is enforced with the group's admission policy, but kept
+	// apart from it so the shared allow and deny rules still apply
+	if cfg.MaxListeners > 0 {
+		subManager.SetMaxSubscribers(group, cfg.MaxListeners)
+	}
+
+	// Subscription lease defaults
+	heartbeatInterval := cfg.HeartbeatInterval
+	if heartbeatInterval <= 0 {
+		heartbeatInterval = DefaultHeartbeatInterval
+	}
+	leaseTimeout := cfg.LeaseTimeout
+	if leaseTimeout <= 0 {
+		leaseTimeout = DefaultLeaseTimeout
+	}
+
+	beaconInterval := cfg.BeaconInterval
+	if beaconInterval <= 0 {
+		beaconInterval = DefaultBeaconInterval
+	}
+
+	// Native multicast: one send reaches every listener that joined the group
+	var mcastGroup net.IP
+	var mcastPort int
+	if mc := cfg.Multicast; mc != nil {
+		sender, ok := transport.(network.MulticastSender)
+		if !ok {
+			return nil, fmt.Errorf("transport does not support native multicast")
+		}
+
+		scope := mc.Scope
+		if scope == 0 {
+			scope = network.DefaultMulticastScope
+		}
+		mcastPort = mc.Port
+		if mcastPort <= 0 {
+			mcastPort = network.DefaultMulticastPort
+		}
+		hopLimit := mc.HopLimit
+		if hopLimit <= 0 {
+			hopLimit = network.DefaultMulticastHopLimit
+		}
+
+		if err := sender.SetMulticastOptions(mc.Interface, hopLimit); err != nil {
+			return nil, fmt.Errorf("failed to enable native multicast: %w", err)
+		}
+		mcastGroup = network.GroupAddress(group, scope)
+		fmt.Printf("📡 Native multicast for group '%s' on [%s]:%d\n", group, mcastGroup, mcastPort)
+	}
+
+	// Archive the exact frames that go out (a relay has none of its own)
+	var recorder *archive.Recorder
+	if cfg.Archive != nil {
+		if cfg.Relay {
+			return nil, fmt.Errorf("archiving is not supported in relay mode")
+		}
+		rec, err := archive.New(*cfg.Archive, archive.Stream{
+			Callsign:   cfg.Callsign,
+			Group:      group,
+			SampleRate: cfg.AudioConfig.SampleRate,
+			Channels:   cfg.AudioConfig.Channels,
+			FrameSize:  cfg.AudioConfig.FrameSize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create archive: %w", err)
+		}
+		recorder = rec
+	}
+
+	cookieSecret := make([]byte, 32)
+	if _, err := cryptorand.Read(cookieSecret); err != nil {
+		return nil, fmt.Errorf("failed to generate cookie secret: %w", err)
+	}
+
 	return &Broadcaster{
-		callsign:        cfg.Callsign,
-		ipv6:            cfg.IPv6,
-		port:            cfg.Port,
-		group:           group,
-		priority:        priority,
-		transport:       transport,
-		audioSource:     audioSource,
-		codec:           codec,
-		config:          cfg.AudioConfig,
-		stopChan:        make(chan struct{}),
-		subManager:      subManager,
-		channelRegistry: channelRegistry,
-		listeners:       make(map[string]*ListenerConn),
+		callsign:          cfg.Callsign,
+		ipv6:              cfg.IPv6,
+		port:              cfg.Port,
+		group:             group,
+		priority:          priority,
+		transport:         transport,
+		audioSource:       audioSource,
+		sourceEnded:       make(chan struct{}, 1),
+		codec:             codec,
+		relay:             cfg.Relay,
+		config:            cfg.AudioConfig,
+		mediaTime:         rand.Uint32(),
+		streamID:          rand.Uint32(),
+		stopChan:          make(chan struct{}),
+		heartbeatInterval: heartbeatInterval,
+		leaseTimeout:      leaseTimeout,
+		beaconInterval:    beaconInterval,
+		cookieSecret:      cookieSecret,
+		mcastGroup:        mcastGroup,
+		mcastPort:         mcastPort,
+		archive:           recorder,
+		vad:               vad,
+		subManager:        subManager,
+		channelRegistry:   channelRegistry,
+		discoveryCache:    cfg.DiscoveryCache,
+		signingKey:        cfg.SigningKey,
+		trustStore:        cfg.TrustStore,
+		keyring:           cfg.Keyring,
+		reports:           make(map[string]*ListenerReport),
+		sendErrors:        make(map[string]uint64),
+		listeners:         make(map[string]*ListenerConn),
 	}, nil
 }
 
@@ -130,16 +342,21 @@ func (b *Broadcaster) Start() error {
 		return fmt.Errorf("broadcaster already running")
 	}
 	b.running = true
+	b.startedAt = time.Now()
 	b.mu.Unlock()
 
-	// Start transport
-	if err := b.transport.Start(); err != nil {
-		return fmt.Errorf("failed to start transport: %w", err)
+	// Start transport (a station host runs its own)
+	if b.host == nil {
+		if err := b.transport.Start(); err != nil {
+			return fmt.Errorf("failed to start transport: %w", err)
+		}
 	}
 
-	// Start audio source
-	if err := b.audioSource.Start(); err != nil {
-		return fmt.Errorf("failed to start audio source: %w", err)
+	// Start audio source (it may already run if it was prepared ahead)
+	if src := b.currentSource(); src != nil && !src.IsRunning() {
+		if err := src.Start(); err != nil {
+			return fmt.Errorf("failed to start audio source: %w", err)
+		}
 	}
 
 	// Register this broadcaster with the subscription manager
@@ -151,27 +368,44 @@ func (b *Broadcaster) Start() error {
 	}
 	b.subManager.RegisterBroadcaster(b.group, broadcaster)
 
-	// Get channel info for logging
-	priorityStr := "normal"
-	if ch, ok := b.channelRegistry.GetByGroup(b.group); ok {
-		priorityStr = ch.Priority.String()
-	}
+	priorityStr := emergency.Priority(b.priority).String()
 	fmt.Printf("Registered broadcaster in group '%s' with priority '%s'\n", b.group, priorityStr)
+	if b.keyring.IsPrivate(b.group) {
+		fmt.Printf("🔒 Private group '%s': audio and metadata are encrypted\n", b.group)
+	}
+	if b.signingKey != nil {
+		fmt.Printf("🔏 Signing packets with station key %s\n", signing.PublicKeyHex(b.signingKey))
+	}
 
-	// Start broadcast loop
-	go b.broadcastLoop()
+	// Start broadcast loop (a relay sends what Forward hands it)
+	if !b.relay {
+		go b.broadcastLoop()
+	}
 
-	// Handle incoming subscriptions and heartbeats
-	go b.subscriptionLoop()
+	// Handle incoming subscriptions and heartbeats (a station host routes them)
+	if b.host == nil {
+		go b.subscriptionLoop()
+	}
 
 	// Monitor listener timeouts
 	go b.heartbeatMonitor()
 
+	// Repeat now-playing metadata
+	go b.metadataLoop()
+
+	// Announce the station
+	go b.beaconLoop()
+
 	return nil
 }
 
-// Stop stops broadcasting
+// Stop stops broadcasting; listeners are told the station shut down
 func (b *Broadcaster) Stop() error {
+	return b.StopWith(protocol.OffAirShutdown, time.Time{})
+}
+
+// stop stops the broadcast loops and the source
+func (b *Broadcaster) stop() error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
@@ -182,8 +416,17 @@ func (b *Broadcaster) Stop() error {
 	b.running = false
 	close(b.stopChan)
 
-	b.audioSource.Stop()
-	b.transport.Stop()
+	if src := b.currentSource(); src != nil {
+		src.Stop()
+	}
+	if b.host == nil {
+		b.transport.Stop()
+	}
+	if b.archive != nil {
+		if err := b.archive.Close(); err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+		}
+	}
 
 	return nil
 }
@@ -201,6 +444,10 @@ func (b *Broadcaster) broadcastLoop() {
 	ticker := time.NewTicker(frameDuration)
 	defer ticker.Stop()
 
+	// Ticks spent without a source, and how many make the station off air
+	idleTicks := 0
+	graceTicks := int(sourceEndedGrace / frameDuration)
+
 	for {
 		select {
 		case <-b.stopChan:
@@ -210,14 +457,32 @@ func (b *Broadcaster) broadcastLoop() {
 		}
 
 		// Read audio frame (as int16 samples)
-		samples, err := b.audioSource.Read()
+		src := b.currentSource()
+		if b.sourceDone(src) {
+			// Waiting for SetSource; if none comes, tell listeners we're off air
+			idleTicks++
+			if idleTicks == graceTicks {
+				b.signOff(protocol.OffAirSourceEnded, time.Time{})
+			}
+			continue
+		}
+		samples, err := src.Read()
 
+		if err == io.EOF {
+			// Track finished: wait for the next source, don't log every tick
+			b.signalSourceEnded(src)
+			continue
+		}
 		if err != nil {
 			if b.seqNum%250 == 0 { // Log errors less frequently
 				fmt.Printf("Audio source read error: %v\n", err)
 			}
 			continue
 		}
+		idleTicks = 0
+
+		// DTX: decide before encoding whether this frame goes out
+		send, comfortNoise := b.dtxSend(samples)
 
 		// Convert int16 samples to bytes for codec
 		pcm := make([]byte, len(samples)*2)
@@ -233,13 +498,21 @@ func (b *Broadcaster) broadcastLoop() {
 			continue
 		}
 
+		// Silence is still encoded and archived, so the encoder and the
+		// recording keep running, but not sent
+		if !send {
+			b.mediaTime += uint32(b.config.FrameSize)
+			b.archiveFrame(encoded)
+			continue
+		}
+
 		// Create audio packet payload
 		audioPayload := protocol.MarshalAudioPayload(&protocol.AudioPacket{
 			CodecType:      protocol.CodecOpus,
 			SampleRate:     uint8(b.config.SampleRate / 1000),
 			Channels:       uint8(b.config.Channels),
 			Bitrate:        uint8(b.config.Bitrate / 1000),
-			FrameTimestamp: uint32(time.Now().UnixMilli()),
+			FrameTimestamp: b.mediaTime,
 			AudioData:      encoded,
 		})
 
@@ -251,19 +524,30 @@ func (b *Broadcaster) broadcastLoop() {
 			audioPayload,
 		)
 		packet.SequenceNum = b.seqNum
+		packet.MediaTimestamp = b.mediaTime
+		packet.StreamID = b.streamID
 		packet.SetPriority(b.priority) // Set priority (Layer 5: Emergency)
+		if comfortNoise {
+			packet.Flags |= protocol.FlagComfortNoise
+		}
 		b.seqNum++
+		b.mediaTime += uint32(b.config.FrameSize)
+
+		// Private channel: encrypt the Opus payload, never fall back to clear
+		if err := b.encrypt(packet); err != nil {
+			fmt.Printf("Encrypt error: %v\n", err)
+			continue
+		}
+		b.sign(packet)
 
 		// Get subscribers for this broadcaster (using multicast overlay)
 		subscribers := b.subManager.GetSubscribersForSource(b.group, b.ipv6)
 
-		// Send to all subscribed listeners (unicast fan-out)
-		for _, sub := range subscribers {
-			err = b.transport.Send(packet, sub.IPv6, sub.Port)
-			if err != nil && b.seqNum%250 == 0 {
-				fmt.Printf("Send error to %s: %v\n", sub.Callsign, err)
-			}
-		}
+		// Send to all subscribed listeners (unicast fan-out, one batch)
+		b.fanOut(packet, subscribers)
+
+		// Keep the frame that went out on the air (before encryption)
+		b.archiveFrame(encoded)
 
 		// Log periodically (every 5 seconds at 50fps)
 		if b.seqNum%250 == 0 {
@@ -274,7 +558,20 @@ func (b *Broadcaster) broadcastLoop() {
 	}
 }
 
-// subscriptionLoop handles incoming SUBSCRIBE and HEARTBEAT packets
+// archiveFrame writes an encoded frame to the archive, if any
+func (b *Broadcaster) archiveFrame(encoded []byte) {
+	if b.archive == nil {
+		return
+	}
+	if err := b.archive.WriteFrame(encoded); err != nil {
+		if b.archiveErrors%250 == 0 { // Log errors less frequently
+			fmt.Printf("⚠️  %v\n", err)
+		}
+		b.archiveErrors++
+	}
+}
+
+// subscriptionLoop handles incoming SUBSCRIBE, HEARTBEAT, UNSUBSCRIBE and signal report packets
 func (b *Broadcaster) subscriptionLoop() {
 	for {
 		select {
@@ -284,27 +581,33 @@ func (b *Broadcaster) subscriptionLoop() {
 		}
 
 		// Receive packet from transport
-		packet, err := b.transport.Receive()
+		packet, from, err := b.transport.Receive()
 		if err != nil {
 			continue
 		}
 
-		// Only log non-heartbeat packets to reduce spam
-		if packet.Type != protocol.PacketTypeHeartbeat {
-			fmt.Printf("Received packet type=%d from %s\n", packet.Type, protocol.BytesToIPv6(packet.SourceIPv6))
+		// Only log non-periodic packets to reduce spam
+		if packet.Type != protocol.PacketTypeHeartbeat && packet.Type != protocol.PacketTypeSignalReport {
+			fmt.Printf("Received packet type=%d from %s\n", packet.Type, from)
 		}
 
 		switch packet.Type {
 		case protocol.PacketTypeSubscribe:
-			b.handleSubscribe(packet)
+			b.handleSubscribe(packet, from)
 		case protocol.PacketTypeHeartbeat:
-			b.handleHeartbeat(packet)
+			b.handleHeartbeat(packet, from)
+		case protocol.PacketTypeUnsubscribe:
+			b.handleUnsubscribe(packet, from)
+		case protocol.PacketTypeDiscoveryReq:
+			b.handleDiscoveryRequest(packet, from)
+		case protocol.PacketTypeSignalReport:
+			b.handleSignalReport(packet, from)
 		}
 	}
 }
 
 // handleSubscribe processes a subscription request
-func (b *Broadcaster) handleSubscribe(packet *protocol.Packet) {
+func (b *Broadcaster) handleSubscribe(packet *protocol.Packet, from *net.UDPAddr) {
 	sub, err := protocol.UnmarshalSubscribe(packet.Payload)
 	if err != nil {
 		fmt.Printf("Invalid subscribe packet: %v\n", err)
@@ -320,13 +623,34 @@ func (b *Broadcaster) handleSubscribe(packet *protocol.Packet) {
 		group = b.group
 	}
 
+	// A listener subscribes itself: never send a challenge to a third party
+	if !sentFrom(from, listenerIP, sub.ListenerPort) {
+		fmt.Printf("⚠️  Ignored SUBSCRIBE for [%s]:%d sent from %s\n", listenerIP, sub.ListenerPort, from)
+		return
+	}
+
+	// Return-routability: activate nothing until the listener echoes our cookie
+	// from the address it claims. Rejections also wait for this, so a spoofed
+	// SUBSCRIBE gets a victim at most one small challenge packet.
+	if !b.validCookie(sub.Cookie, listenerIP, sub.ListenerPort, group) {
+		b.sendSubscribeChallenge(listenerIP, sub.ListenerPort, group)
+		return
+	}
+
+	// Only accept groups that some broadcaster actually serves
+	if len(b.subManager.GetBroadcasters(group)) == 0 {
+		fmt.Printf("❌ Rejected subscriber %s: unknown group '%s'\n", callsign, group)
+		b.sendSubscribeAck(listenerIP, int(sub.ListenerPort), group, protocol.RejectUnknownGroup)
+		return
+	}
+
 	// Extract SSM source (nil = regular multicast)
 	var ssmSource net.IP
 	if !protocol.IsZeroIPv6(sub.SSMSource) {
 		ssmSource = protocol.BytesToIPv6(sub.SSMSource)
 	}
 
-	// Create subscriber
+	// Create subscriber (its address is verified, so the policy can trust it)
 	subscriber := &multicast.Subscriber{
 		IPv6:      listenerIP,
 		Port:      int(sub.ListenerPort),
@@ -335,11 +659,32 @@ func (b *Broadcaster) handleSubscribe(packet *protocol.Packet) {
 		SSMSource: ssmSource,
 	}
 
-	// Add to subscription manager
-	b.subManager.Subscribe(multicast.SubscribeRequest{
+	// Its callsign only counts for callsign rules if a trusted key signed for it
+	if b.trustStore != nil && strings.EqualFold(packet.GetCallsign(), callsign) &&
+		b.trustStore.Check(packet) == signing.Trusted {
+		subscriber.VerifiedCallsign = callsign
+	}
+
+	// Add to subscription manager, which applies the group's admission policy
+	err = b.subManager.Subscribe(multicast.SubscribeRequest{
 		Group:      group,
 		Subscriber: subscriber,
 	})
+	if err != nil {
+		result := rejectReason(err)
+		fmt.Printf("❌ Rejected subscriber %s [%s]: %v (group '%s')\n", callsign, listenerIP, err, group)
+		b.sendSubscribeAck(listenerIP, int(sub.ListenerPort), group, result)
+		return
+	}
+
+	// Confirm the subscription and hand out the lease
+	b.sendSubscribeAck(listenerIP, int(sub.ListenerPort), group, protocol.SubscribeAccepted)
+
+	// Introduce the station and tell the new listener what is playing right away
+	b.transport.Send(b.beaconPacket(), listenerIP, int(sub.ListenerPort))
+	if packet := b.metadataPacket(); packet != nil {
+		b.transport.Send(packet, listenerIP, int(sub.ListenerPort))
+	}
 
 	multicastType := "Regular"
 	if subscriber.IsSSM() {
@@ -361,8 +706,205 @@ func (b *Broadcaster) handleSubscribe(packet *protocol.Packet) {
 	b.listenersMux.Unlock()
 }
 
+// rejectReason maps an admission error to the SUBSCRIBE-ACK result
+func rejectReason(err error) uint8 {
+	switch {
+	case errors.Is(err, multicast.ErrGroupFull):
+		return protocol.RejectFull
+	case errors.Is(err, multicast.ErrDenied):
+		return protocol.RejectDenied
+	case errors.Is(err, multicast.ErrBanned):
+		return protocol.RejectBanned
+	default:
+		return protocol.RejectUnknownGroup
+	}
+}
+
+// hasSubscriber reports whether a listener is already subscribed to a group
+func (b *Broadcaster) hasSubscriber(group string, ipv6 net.IP, port int) bool {
+	for _, sub := range b.subManager.GetSubscribers(group) {
+		if sub.IPv6.Equal(ipv6) && sub.Port == port {
+			return true
+		}
+	}
+	return false
+}
+
+// sendSubscribeAck answers a SUBSCRIBE with the result and lease parameters
+func (b *Broadcaster) sendSubscribeAck(listenerIP net.IP, port int, group string, result uint8) {
+	var ipv6Bytes [16]byte
+	copy(ipv6Bytes[:], b.ipv6.To16())
+
+	ackPayload := &protocol.SubscribeAckPayload{
+		Result:            result,
+		Group:             protocol.StringToGroup(group),
+		HeartbeatInterval: uint32(b.heartbeatInterval / time.Millisecond),
+		LeaseTimeout:      uint32(b.leaseTimeout / time.Millisecond),
+	}
+	if result == protocol.SubscribeAccepted && b.mcastGroup != nil {
+		copy(ackPayload.MulticastGroup[:], b.mcastGroup.To16())
+		ackPayload.MulticastPort = uint16(b.mcastPort)
+	}
+
+	packet := protocol.NewPacket(
+		protocol.PacketTypeSubscribeAck,
+		ipv6Bytes,
+		b.callsign,
+		protocol.MarshalSubscribeAck(ackPayload),
+	)
+	b.sign(packet)
+
+	if err := b.transport.Send(packet, listenerIP, port); err != nil {
+		fmt.Printf("⚠️  Failed to send SUBSCRIBE-ACK to %s:%d: %v\n", listenerIP, port, err)
+	}
+}
+
+// handleUnsubscribe removes a listener that is leaving cleanly
+func (b *Broadcaster) handleUnsubscribe(packet *protocol.Packet, from *net.UDPAddr) {
+	unsub, err := protocol.UnmarshalUnsubscribe(packet.Payload)
+	if err != nil {
+		fmt.Printf("Invalid unsubscribe packet: %v\n", err)
+		return
+	}
+
+	listenerIP := protocol.BytesToIPv6(unsub.ListenerIPv6)
+	callsign := packet.GetCallsign()
+
+	// Only the listener itself may end its subscription
+	if !sentFrom(from, listenerIP, unsub.ListenerPort) {
+		fmt.Printf("⚠️  Ignored UNSUBSCRIBE for [%s]:%d sent from %s\n", listenerIP, unsub.ListenerPort, from)
+		return
+	}
+
+	// Extract group name (use broadcaster's group if not specified)
+	group := protocol.GetGroupString(unsub.Group)
+	if group == "" {
+		group = b.group
+	}
+
+	err = b.subManager.Unsubscribe(multicast.UnsubscribeRequest{
+		Group: group,
+		IPv6:  listenerIP,
+		Port:  int(unsub.ListenerPort),
+	})
+	if err != nil {
+		fmt.Printf("⚠️  Failed to unsubscribe %s from group '%s': %v\n", callsign, group, err)
+	} else {
+		fmt.Printf("👋 Subscriber left: %s from group '%s' (total: %d)\n",
+			callsign, group, len(b.subManager.GetSubscribers(group)))
+	}
+
+	b.forgetListener(listenerIP, unsub.ListenerPort)
+}
+
+// sentFrom reports whether a packet came from the listener address in its payload.
+// Anyone can write any address into a payload, but not receive at a forged
+// UDP source. Port 0 (old heartbeats) only checks the address.
+func sentFrom(from *net.UDPAddr, ipv6 net.IP, port uint16) bool {
+	if from == nil || !from.IP.Equal(ipv6) {
+		return false
+	}
+	return port == 0 || from.Port == int(port)
+}
+
+// forgetListener drops the reports and legacy entry of a listener that left
+func (b *Broadcaster) forgetListener(listenerIP net.IP, port uint16) {
+	b.removeReport(listenerIP, port)
+
+	// Legacy: Also remove from old listeners map
+	listenerKey := fmt.Sprintf("%s:%d", listenerIP.String(), port)
+	b.listenersMux.Lock()
+	delete(b.listeners, listenerKey)
+	b.listenersMux.Unlock()
+}
+
+// handleDiscoveryRequest answers a mesh discovery query with the stations
+// hosted in this process and any stations heard of through discovery
+func (b *Broadcaster) handleDiscoveryRequest(packet *protocol.Packet, from *net.UDPAddr) {
+	req, err := protocol.UnmarshalDiscoveryRequest(packet.Payload)
+	if err != nil {
+		fmt.Printf("Invalid discovery request: %v\n", err)
+		return
+	}
+
+	filter := protocol.GetGroupString(req.Group)
+	records := b.hostedStations(filter)
+
+	// Stations heard of elsewhere are one hop further away
+	if b.discoveryCache != nil {
+		for _, r := range b.discoveryCache.List(filter) {
+			r.Hops++
+			records = append(records, r.ToStation())
+		}
+	}
+
+	var ipv6Bytes [16]byte
+	copy(ipv6Bytes[:], b.ipv6.To16())
+
+	resp := protocol.NewPacket(
+		protocol.PacketTypeDiscoveryResp,
+		ipv6Bytes,
+		b.callsign,
+		protocol.MarshalDiscoveryResponse(&protocol.DiscoveryResponsePayload{
+			RequestID: req.RequestID,
+			Records:   records,
+		}),
+	)
+	b.sign(resp)
+
+	// Answer the real sender, not the in-band reply address: responses are
+	// larger than requests and must not be reflectable at a third party
+	if err := b.transport.Send(resp, from.IP, from.Port); err != nil {
+		fmt.Printf("⚠️  Failed to send discovery response to %s: %v\n", from, err)
+	}
+}
+
+// hostedStations lists every broadcaster registered with the subscription
+// manager, optionally filtered by group (empty = all)
+func (b *Broadcaster) hostedStations(filter string) []protocol.StationRecord {
+	records := make([]protocol.StationRecord, 0)
+
+	for _, group := range b.subManager.ListGroups() {
+		if filter != "" && group != filter {
+			continue
+		}
+
+		priority := uint8(emergency.PriorityNormal)
+		if ch, ok := b.channelRegistry.GetByGroup(group); ok {
+			priority = uint8(ch.Priority)
+		}
+		stream := b.streamFor(group)
+
+		for _, bc := range b.subManager.GetBroadcasters(group) {
+			var callsign [16]byte
+			copy(callsign[:], []byte(bc.Callsign))
+
+			// Only the bitrate and priority of our own streams are known
+			bitrate := uint16(0)
+			recordPriority := priority
+			if stream != nil && bc.IPv6.Equal(stream.ipv6) && bc.Port == stream.port {
+				bitrate = uint16(stream.config.Bitrate / 1000)
+				recordPriority = stream.priority
+			}
+
+			records = append(records, protocol.StationRecord{
+				IPv6:      protocol.IPv6ToBytes(bc.IPv6),
+				Port:      uint16(bc.Port),
+				Callsign:  callsign,
+				Group:     protocol.StringToGroup(group),
+				Priority:  recordPriority,
+				CodecType: protocol.CodecOpus,
+				Bitrate:   bitrate,
+				TTL:       uint16(discovery.DefaultTTL / time.Second),
+			})
+		}
+	}
+
+	return records
+}
+
 // handleHeartbeat processes a heartbeat from listener
-func (b *Broadcaster) handleHeartbeat(packet *protocol.Packet) {
+func (b *Broadcaster) handleHeartbeat(packet *protocol.Packet, from *net.UDPAddr) {
 	hb, err := protocol.UnmarshalHeartbeat(packet.Payload)
 	if err != nil {
 		fmt.Printf("⚠️  Failed to unmarshal heartbeat: %v\n", err)
@@ -370,6 +912,11 @@ func (b *Broadcaster) handleHeartbeat(packet *protocol.Packet) {
 	}
 
 	listenerIP := protocol.BytesToIPv6(hb.ListenerIPv6)
+
+	// A forged heartbeat must not keep someone else's lease alive
+	if !sentFrom(from, listenerIP, hb.ListenerPort) {
+		return
+	}
 	updated := false
 
 	// Update heartbeat in subscription manager for all groups
@@ -377,23 +924,49 @@ func (b *Broadcaster) handleHeartbeat(packet *protocol.Packet) {
 	for _, group := range b.subManager.ListGroups() {
 		subs := b.subManager.GetSubscribers(group)
 		for _, sub := range subs {
-			if sub.IPv6.Equal(listenerIP) {
-				err := b.subManager.Heartbeat(group, listenerIP, sub.Port)
-				if err != nil {
-					fmt.Printf("⚠️  Failed to update heartbeat for %s:%d in group %s: %v\n",
-						listenerIP, sub.Port, group, err)
+			// Old heartbeats carry no port and refresh every port of the address
+			if !sub.IPv6.Equal(listenerIP) || (hb.ListenerPort != 0 && sub.Port != int(hb.ListenerPort)) {
+				continue
+			}
+			err := b.subManager.Heartbeat(group, listenerIP, sub.Port)
+			if err != nil {
+				fmt.Printf("⚠️  Failed to update heartbeat for %s:%d in group %s: %v\n",
+					listenerIP, sub.Port, group, err)
+				continue
+			}
+			updated = true
+
+			// Only groups we (or our station host) send natively can be native
+			stream := b.streamFor(group)
+			if stream == nil {
+				continue
+			}
+			native := stream.mcastGroup != nil && hb.Flags&protocol.HeartbeatFlagNativeMulticast != 0
+			if sub.Native != native {
+				b.subManager.SetNative(group, listenerIP, sub.Port, native)
+				if native {
+					fmt.Printf("📡 %s now receives native multicast\n", sub.Callsign)
 				} else {
-					updated = true
+					fmt.Printf("📡 %s fell back to unicast\n", sub.Callsign)
 				}
 			}
 		}
 	}
 
+	// A listener whose lease ran out (say, during an outage) still thinks it is
+	// subscribed: a challenge tells it to subscribe again
 	if !updated {
 		fmt.Printf("⚠️  Received heartbeat from unknown listener: %s (no matching subscriber found)\n", listenerIP)
+		if hb.ListenerPort != 0 {
+			b.sendSubscribeChallenge(listenerIP, hb.ListenerPort, b.group)
+		}
 	}
 
-	// Legacy: Also update old listeners map for backward compatibility
+	b.touchListener(listenerIP)
+}
+
+// touchListener refreshes a listener in the legacy listeners map
+func (b *Broadcaster) touchListener(listenerIP net.IP) {
 	b.listenersMux.Lock()
 	for key, listener := range b.listeners {
 		if listener.IPv6.Equal(listenerIP) {
@@ -407,7 +980,8 @@ func (b *Broadcaster) handleHeartbeat(packet *protocol.Packet) {
 
 // heartbeatMonitor removes listeners that haven't sent heartbeat
 func (b *Broadcaster) heartbeatMonitor() {
-	ticker := time.NewTicker(10 * time.Second)
+	// Check every two heartbeat intervals (10s with the default lease)
+	ticker := time.NewTicker(b.heartbeatInterval * 2)
 	defer ticker.Stop()
 
 	for {
@@ -424,7 +998,7 @@ func (b *Broadcaster) heartbeatMonitor() {
 
 			// Prune stale subscribers using multicast overlay
 			beforeSubs := len(b.subManager.GetSubscribers(b.group))
-			prunedSubs, prunedBroadcasters := b.subManager.PruneStale(15 * time.Second)
+			prunedSubs, prunedBroadcasters := b.subManager.PruneStale(b.leaseTimeout)
 			afterSubs := len(b.subManager.GetSubscribers(b.group))
 
 			if prunedSubs > 0 || prunedBroadcasters > 0 {
@@ -432,10 +1006,13 @@ func (b *Broadcaster) heartbeatMonitor() {
 					prunedSubs, prunedBroadcasters, b.group, beforeSubs, afterSubs)
 			}
 
+			// Forget reception of listeners that went silent
+			b.pruneReports(b.leaseTimeout)
+
 			// Legacy: Also prune old listeners map
 			b.listenersMux.Lock()
 			for key, listener := range b.listeners {
-				if time.Since(listener.LastSeen) > 15*time.Second {
+				if time.Since(listener.LastSeen) > b.leaseTimeout {
 					fmt.Printf("Listener timeout (legacy): %s\n", listener.Callsign)
 					delete(b.listeners, key)
 				}
@@ -448,6 +1025,155 @@ func (b *Broadcaster) heartbeatMonitor() {
 	}
 }
 
+// SetMetadata sets the now-playing metadata and pushes it to all subscribers.
+// Position is advanced automatically from the moment it is set.
+func (b *Broadcaster) SetMetadata(md protocol.Metadata) {
+	b.metadataMu.Lock()
+	b.metadata = &md
+	b.metadataSetAt = time.Now()
+	b.metadataMu.Unlock()
+
+	if b.archive != nil {
+		b.archive.SetMetadata(md)
+	}
+
+	if b.IsRunning() {
+		b.sendMetadata()
+	}
+}
+
+// GetMetadata returns the current now-playing metadata, if any
+func (b *Broadcaster) GetMetadata() (protocol.Metadata, bool) {
+	b.metadataMu.Lock()
+	defer b.metadataMu.Unlock()
+
+	if b.metadata == nil {
+		return protocol.Metadata{}, false
+	}
+	return b.metadata.At(time.Since(b.metadataSetAt)), true
+}
+
+// metadataPacket builds a METADATA packet from the current metadata (nil if none set)
+func (b *Broadcaster) metadataPacket() *protocol.Packet {
+	md, ok := b.GetMetadata()
+	if !ok {
+		return nil
+	}
+
+	var ipv6Bytes [16]byte
+	copy(ipv6Bytes[:], b.ipv6.To16())
+
+	packet := protocol.NewPacket(
+		protocol.PacketTypeMetadata,
+		ipv6Bytes,
+		b.callsign,
+		protocol.MarshalMetadata(&md),
+	)
+	packet.SetPriority(b.priority)
+	if err := b.encrypt(packet); err != nil {
+		fmt.Printf("Encrypt error: %v\n", err)
+		return nil
+	}
+	b.sign(packet)
+	return packet
+}
+
+// sendMetadata sends the current metadata to all subscribers
+func (b *Broadcaster) sendMetadata() {
+	packet := b.metadataPacket()
+	if packet == nil {
+		return
+	}
+
+	for _, sub := range b.subManager.GetSubscribersForSource(b.group, b.ipv6) {
+		if err := b.transport.Send(packet, sub.IPv6, sub.Port); err != nil {
+			fmt.Printf("⚠️  Failed to send metadata to %s: %v\n", sub.Callsign, err)
+		}
+	}
+}
+
+// metadataLoop periodically repeats the now-playing metadata
+func (b *Broadcaster) metadataLoop() {
+	ticker := time.NewTicker(metadataInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			b.sendMetadata()
+		case <-b.stopChan:
+			return
+		}
+	}
+}
+
+// beaconPacket builds a BEACON packet describing this station
+func (b *Broadcaster) beaconPacket() *protocol.Packet {
+	var ipv6Bytes [16]byte
+	copy(ipv6Bytes[:], b.ipv6.To16())
+
+	b.mu.Lock()
+	uptime := time.Since(b.startedAt)
+	b.mu.Unlock()
+
+	beacon := &protocol.BeaconPayload{
+		Group:         protocol.StringToGroup(b.group),
+		Priority:      b.priority,
+		CodecType:     protocol.CodecOpus,
+		SampleRate:    uint8(b.config.SampleRate / 1000),
+		Channels:      uint8(b.config.Channels),
+		Bitrate:       uint16(b.config.Bitrate / 1000),
+		ListenerCount: uint16(len(b.subManager.GetSubscribers(b.group))),
+		Uptime:        uint32(uptime / time.Second),
+		Interval:      uint32(b.beaconInterval / time.Millisecond),
+	}
+
+	packet := protocol.NewPacket(
+		protocol.PacketTypeBeacon,
+		ipv6Bytes,
+		b.callsign,
+		protocol.MarshalBeacon(beacon),
+	)
+	packet.SetPriority(b.priority)
+	b.sign(packet)
+	return packet
+}
+
+// beaconLoop periodically announces the station to all subscribers
+func (b *Broadcaster) beaconLoop() {
+	ticker := time.NewTicker(b.beaconInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			packet := b.beaconPacket()
+			for _, sub := range b.subManager.GetSubscribersForSource(b.group, b.ipv6) {
+				if err := b.transport.Send(packet, sub.IPv6, sub.Port); err != nil {
+					fmt.Printf("⚠️  Failed to send beacon to %s: %v\n", sub.Callsign, err)
+				}
+			}
+		case <-b.stopChan:
+			return
+		}
+	}
+}
+
+// encrypt seals the packet payload if this is a private group (no-op otherwise)
+func (b *Broadcaster) encrypt(packet *protocol.Packet) error {
+	if !b.keyring.IsPrivate(b.group) {
+		return nil
+	}
+	return b.keyring.Seal(b.group, packet)
+}
+
+// sign adds the station signature to a finished packet (no-op without a key)
+func (b *Broadcaster) sign(packet *protocol.Packet) {
+	if b.signingKey != nil {
+		packet.Sign(b.signingKey)
+	}
+}
+
 // GetIPv6 returns the broadcaster's IPv6 address
 func (b *Broadcaster) GetIPv6() net.IP {
 	return b.ipv6
@@ -458,6 +1184,11 @@ func (b *Broadcaster) GetCallsign() string {
 	return b.callsign
 }
 
+// GetGroup returns the group this broadcaster serves
+func (b *Broadcaster) GetGroup() string {
+	return b.group
+}
+
 // IsRunning returns whether the broadcaster is running
 func (b *Broadcaster) IsRunning() bool {
 	b.mu.Lock()
@@ -465,6 +1196,11 @@ func (b *Broadcaster) IsRunning() bool {
 	return b.running
 }
 
+// GetTransportStats returns the traffic and drop counters of the broadcaster's socket
+func (b *Broadcaster) GetTransportStats() network.TransportStats {
+	return b.transport.Stats()
+}
+
 // GetListenerCount returns the number of connected listeners
 func (b *Broadcaster) GetListenerCount() int {
 	b.listenersMux.RLock()
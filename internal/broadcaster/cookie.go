@@ -0,0 +1,70 @@
+package broadcaster
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/meshradio/meshradio/pkg/protocol"
+)
+
+// cookieWindow is the lifetime of a SUBSCRIBE challenge cookie.
+// Cookies from the current and the previous window are accepted.
+const cookieWindow = 30 * time.Second
+
+// subscribeCookie derives the challenge cookie for a claimed listener address.
+// Cookies are stateless: nothing is stored until the listener echoes one back.
+func (b *Broadcaster) subscribeCookie(ipv6 net.IP, port uint16, group string, window int64) [16]byte {
+	mac := hmac.New(sha256.New, b.cookieSecret)
+	mac.Write(ipv6.To16())
+	binary.Write(mac, binary.BigEndian, port)
+	binary.Write(mac, binary.BigEndian, window)
+	mac.Write([]byte(group))
+
+	var cookie [16]byte
+	copy(cookie[:], mac.Sum(nil))
+	return cookie
+}
+
+// validCookie checks an echoed cookie against the claimed listener address
+func (b *Broadcaster) validCookie(cookie [16]byte, ipv6 net.IP, port uint16, group string) bool {
+	window := time.Now().UnixNano() / int64(cookieWindow)
+	for _, w := range []int64{window, window - 1} {
+		expected := b.subscribeCookie(ipv6, port, group, w)
+		if hmac.Equal(cookie[:], expected[:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// sendSubscribeChallenge sends a cookie to the claimed listener address.
+// Only a host that really receives there can echo it back, so a spoofed
+// SUBSCRIBE cannot point the stream at a third party. Once subscribed, the
+// listener's HEARTBEAT, UNSUBSCRIBE and signal reports must come from that
+// same verified address (see sentFrom).
+func (b *Broadcaster) sendSubscribeChallenge(listenerIP net.IP, port uint16, group string) {
+	var ipv6Bytes [16]byte
+	copy(ipv6Bytes[:], b.ipv6.To16())
+
+	window := time.Now().UnixNano() / int64(cookieWindow)
+	challenge := &protocol.SubscribeChallengePayload{
+		Cookie: b.subscribeCookie(listenerIP, port, group, window),
+		Group:  protocol.StringToGroup(group),
+	}
+
+	packet := protocol.NewPacket(
+		protocol.PacketTypeSubscribeChallenge,
+		ipv6Bytes,
+		b.callsign,
+		protocol.MarshalSubscribeChallenge(challenge),
+	)
+	b.sign(packet)
+
+	if err := b.transport.Send(packet, listenerIP, int(port)); err != nil {
+		fmt.Printf("⚠️  Failed to send SUBSCRIBE challenge to %s:%d: %v\n", listenerIP, port, err)
+	}
+}
@@ -0,0 +1,32 @@
+package broadcaster
+
+import "time"
+
+// dtxKeepaliveInterval is how often a comfort noise frame goes out during
+// silence, as with Opus DTX. It keeps listeners' reception alive and gives
+// them the background to play.
+const dtxKeepaliveInterval = 400 * time.Millisecond
+
+// dtxSend runs voice activity detection on a frame and decides whether to
+// send it. During silence only every dtxKeepaliveInterval a frame goes out,
+// flagged as comfort noise. Sequence numbers stay contiguous across the
+// gaps, so listeners don't count them as loss.
+func (b *Broadcaster) dtxSend(samples []int16) (send, comfortNoise bool) {
+	if b.vad == nil {
+		return true, false
+	}
+	if b.vad.Active(samples) {
+		b.silentFrames = 0
+		return true, false
+	}
+
+	frameDuration := time.Duration(b.config.FrameSize) * time.Second / time.Duration(b.config.SampleRate)
+	keepalive := int(dtxKeepaliveInterval / frameDuration)
+	if keepalive < 1 {
+		keepalive = 1
+	}
+
+	send = b.silentFrames%keepalive == 0
+	b.silentFrames++
+	return send, send
+}
@@ -0,0 +1,77 @@
+package broadcaster
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/meshradio/meshradio/pkg/multicast"
+	"github.com/meshradio/meshradio/pkg/network"
+	"github.com/meshradio/meshradio/pkg/protocol"
+)
+
+// fanOut sends one frame to every subscriber in a single batch
+// The packet is marshaled once; failed destinations are counted per
+// subscriber and never hold up the frame for the others. With native
+// multicast the frame also goes once to the group address, and only
+// subscribers that don't confirm receiving it get a unicast copy.
+// Safe to call from broadcastLoop and Forward at once.
+func (b *Broadcaster) fanOut(packet *protocol.Packet, subscribers []*multicast.Subscriber) {
+	if len(subscribers) == 0 {
+		return
+	}
+
+	b.fanoutMu.Lock()
+	defer b.fanoutMu.Unlock()
+
+	if b.mcastGroup != nil {
+		if err := b.transport.Send(packet, b.mcastGroup, b.mcastPort); err != nil && packet.SequenceNum%250 == 0 {
+			fmt.Printf("Multicast send error: %v\n", err)
+		}
+
+		unicast := make([]*multicast.Subscriber, 0, len(subscribers))
+		for _, sub := range subscribers {
+			if !sub.Native {
+				unicast = append(unicast, sub)
+			}
+		}
+		subscribers = unicast
+		if len(subscribers) == 0 {
+			return
+		}
+	}
+
+	if cap(b.fanoutAddrs) < len(subscribers) {
+		b.fanoutAddrs = make([]net.UDPAddr, len(subscribers))
+		b.fanoutTargets = make([]*net.UDPAddr, len(subscribers))
+	}
+	addrs := b.fanoutAddrs[:len(subscribers)]
+	targets := b.fanoutTargets[:len(subscribers)]
+	for i, sub := range subscribers {
+		addrs[i] = net.UDPAddr{IP: sub.IPv6, Port: sub.Port}
+		targets[i] = &addrs[i]
+	}
+
+	failed, err := b.transport.SendBatch(packet, targets)
+	if err != nil {
+		fmt.Printf("Send error: %v\n", err)
+		return
+	}
+	if len(failed) > 0 {
+		b.recordSendErrors(failed)
+	}
+}
+
+// recordSendErrors counts failed sends per subscriber
+func (b *Broadcaster) recordSendErrors(failed []network.SendError) {
+	b.reportsMu.Lock()
+	defer b.reportsMu.Unlock()
+
+	for _, f := range failed {
+		key := reportKey(f.Addr.IP, uint16(f.Addr.Port))
+		b.sendErrors[key]++
+		// Log the first failure and then every 250th, like the frame log
+		if n := b.sendErrors[key]; n == 1 || n%250 == 0 {
+			fmt.Printf("Send error to %s (%d so far): %v\n", f.Addr, n, f.Err)
+		}
+	}
+}
@@ -0,0 +1,383 @@
+package broadcaster
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/meshradio/meshradio/pkg/archive"
+	"github.com/meshradio/meshradio/pkg/audio"
+	"github.com/meshradio/meshradio/pkg/discovery"
+	"github.com/meshradio/meshradio/pkg/emergency"
+	"github.com/meshradio/meshradio/pkg/encryption"
+	"github.com/meshradio/meshradio/pkg/multicast"
+	"github.com/meshradio/meshradio/pkg/network"
+	"github.com/meshradio/meshradio/pkg/protocol"
+	"github.com/meshradio/meshradio/pkg/signing"
+)
+
+// Host runs several named streams from one process
+// All streams share one transport, so one port, and one subscription
+// manager. Each stream is a Broadcaster with its own group, source, codec
+// config and priority; the host receives for all of them and routes
+// SUBSCRIBE and the other listener packets to the stream of their group.
+type Host struct {
+	callsign   string
+	ipv6       net.IP
+	port       int
+	transport  network.Transport
+	subManager *multicast.SubscriptionManager
+	config     HostConfig
+
+	streams      map[string]*Broadcaster // key: group
+	defaultGroup string                  // First stream added, serves packets without a group
+	running      bool
+	stopChan     chan struct{}
+	mu           sync.Mutex
+}
+
+// HostConfig holds the settings shared by all streams of a station host
+type HostConfig struct {
+	Callsign          string
+	IPv6              net.IP
+	Port              int
+	SubscriptionMgr   *multicast.SubscriptionManager // Optional: shared subscription manager. If nil, creates new one.
+	HeartbeatInterval time.Duration                 // Optional: heartbeat interval offered to listeners (default: 5s)
+	LeaseTimeout      time.Duration                 // Optional: prune listeners silent for this long (default: 15s)
+	BeaconInterval    time.Duration                 // Optional: station beacon interval (default: 5s)
+	DiscoveryCache    *discovery.Cache              // Optional: stations heard of, included in discovery responses
+	SigningKey        ed25519.PrivateKey            // Optional: station key, signs every packet when set
+	TrustStore        *signing.TrustStore           // Optional: listener keys; callsign rules only match SUBSCRIBEs signed by one
+	Keyring           *encryption.Keyring           // Optional: encrypts streams whose group it holds a key for
+	Transport         network.Transport             // Optional: packet transport (default: UDP socket on Port)
+	Multicast         *network.MulticastConfig      // Optional: also send every stream natively to its group's IPv6 multicast address
+}
+
+// StreamConfig describes one stream of a station host
+type StreamConfig struct {
+	Group        string
+	AudioConfig  audio.StreamConfig
+	AudioSource  audio.AudioSource   // Optional: custom audio source. If nil, uses microphone.
+	Priority     *emergency.Priority // Optional: broadcast priority (default: the group's channel priority)
+	Callsign     string              // Optional: callsign of this stream (default: the host's)
+	MaxListeners int                 // Optional: reject subscriptions beyond this count on top of the group policy (0 = unlimited)
+	Archive      *archive.Config     // Optional: record this stream into rolling Ogg Opus files
+	DTX          *bool               // Optional: send only comfort noise keepalives during silence (default: on for voice channels)
+}
+
+// NewHost creates a station host without streams
+func NewHost(cfg HostConfig) (*Host, error) {
+	transport := cfg.Transport
+	if transport == nil {
+		udp, err := network.NewTransport(cfg.Port)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create transport: %w", err)
+		}
+		transport = udp
+	}
+
+	subManager := cfg.SubscriptionMgr
+	if subManager == nil {
+		subManager = multicast.NewSubscriptionManager()
+	}
+
+	return &Host{
+		callsign:   cfg.Callsign,
+		ipv6:       cfg.IPv6,
+		port:       cfg.Port,
+		transport:  transport,
+		subManager: subManager,
+		config:     cfg,
+		streams:    make(map[string]*Broadcaster),
+		stopChan:   make(chan struct{}),
+	}, nil
+}
+
+// AddStream creates a stream for a group; it starts at once if the host runs
+func (h *Host) AddStream(cfg StreamConfig) (*Broadcaster, error) {
+	callsign := cfg.Callsign
+	if callsign == "" {
+		callsign = h.callsign
+	}
+
+	b, err := New(Config{
+		Callsign:          callsign,
+		IPv6:              h.ipv6,
+		Port:              h.port,
+		Group:             cfg.Group,
+		AudioConfig:       cfg.AudioConfig,
+		AudioSource:       cfg.AudioSource,
+		SubscriptionMgr:   h.subManager,
+		HeartbeatInterval: h.config.HeartbeatInterval,
+		LeaseTimeout:      h.config.LeaseTimeout,
+		MaxListeners:      cfg.MaxListeners,
+		BeaconInterval:    h.config.BeaconInterval,
+		DiscoveryCache:    h.config.DiscoveryCache,
+		SigningKey:        h.config.SigningKey,
+		TrustStore:        h.config.TrustStore,
+		Keyring:           h.config.Keyring,
+		Transport:         h.transport,
+		Multicast:         h.config.Multicast,
+		Priority:          cfg.Priority,
+		Archive:           cfg.Archive,
+		DTX:               cfg.DTX,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stream '%s': %w", cfg.Group, err)
+	}
+	b.host = h
+
+	h.mu.Lock()
+	if _, exists := h.streams[b.group]; exists {
+		h.mu.Unlock()
+		return nil, fmt.Errorf("stream for group '%s' already exists", b.group)
+	}
+	h.streams[b.group] = b
+	if h.defaultGroup == "" {
+		h.defaultGroup = b.group
+	}
+	running := h.running
+	h.mu.Unlock()
+
+	if running {
+		if err := b.Start(); err != nil {
+			h.mu.Lock()
+			delete(h.streams, b.group)
+			h.mu.Unlock()
+			return nil, fmt.Errorf("failed to start stream '%s': %w", b.group, err)
+		}
+	}
+
+	return b, nil
+}
+
+// RemoveStream stops a stream and drops it from the host
+func (h *Host) RemoveStream(group string) error {
+	h.mu.Lock()
+	b, ok := h.streams[group]
+	if !ok {
+		h.mu.Unlock()
+		return fmt.Errorf("no stream for group '%s'", group)
+	}
+	delete(h.streams, group)
+	if h.defaultGroup == group {
+		h.defaultGroup = ""
+		for g := range h.streams {
+			h.defaultGroup = g
+			break
+		}
+	}
+	h.mu.Unlock()
+
+	b.Stop()
+	h.subManager.UnregisterBroadcaster(group, h.ipv6)
+	return nil
+}
+
+// Stream returns the stream of a group
+func (h *Host) Stream(group string) (*Broadcaster, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	b, ok := h.streams[group]
+	return b, ok
+}
+
+// Streams returns all streams of the host
+func (h *Host) Streams() []*Broadcaster {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	streams := make([]*Broadcaster, 0, len(h.streams))
+	for _, b := range h.streams {
+		streams = append(streams, b)
+	}
+	return streams
+}
+
+// Start opens the shared transport and starts every stream
+func (h *Host) Start() error {
+	h.mu.Lock()
+	if h.running {
+		h.mu.Unlock()
+		return fmt.Errorf("station host already running")
+	}
+	h.running = true
+	h.mu.Unlock()
+
+	if err := h.transport.Start(); err != nil {
+		h.mu.Lock()
+		h.running = false
+		h.mu.Unlock()
+		return fmt.Errorf("failed to start transport: %w", err)
+	}
+
+	streams := h.Streams()
+	for i, b := range streams {
+		if err := b.Start(); err != nil {
+			// Roll back the streams started so far and the failed one (nobody
+			// has subscribed yet, so there is no one to sign off to)
+			for _, started := range streams[:i+1] {
+				started.stop()
+			}
+			h.transport.Stop()
+			h.mu.Lock()
+			h.running = false
+			h.mu.Unlock()
+			return fmt.Errorf("failed to start stream '%s': %w", b.group, err)
+		}
+	}
+
+	fmt.Printf("📻 Station host on port %d with %d stream(s)\n", h.port, len(h.Streams()))
+
+	go h.receiveLoop()
+
+	return nil
+}
+
+// Stop stops every stream and closes the shared transport
+func (h *Host) Stop() error {
+	h.mu.Lock()
+	if !h.running {
+		h.mu.Unlock()
+		return nil
+	}
+	h.running = false
+	close(h.stopChan)
+	h.mu.Unlock()
+
+	for _, b := range h.Streams() {
+		b.Stop()
+	}
+	h.transport.Stop()
+
+	return nil
+}
+
+// IsRunning returns whether the host is running
+func (h *Host) IsRunning() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.running
+}
+
+// GetTransportStats returns the traffic and drop counters of the shared socket
+func (h *Host) GetTransportStats() network.TransportStats {
+	return h.transport.Stats()
+}
+
+// receiveLoop routes listener packets to the stream of their group
+func (h *Host) receiveLoop() {
+	for {
+		select {
+		case <-h.stopChan:
+			return
+		default:
+		}
+
+		packet, from, err := h.transport.Receive()
+		if err != nil {
+			continue
+		}
+
+		// Only log non-periodic packets to reduce spam
+		if packet.Type != protocol.PacketTypeHeartbeat && packet.Type != protocol.PacketTypeSignalReport {
+			fmt.Printf("Received packet type=%d from %s\n", packet.Type, from)
+		}
+
+		switch packet.Type {
+		case protocol.PacketTypeSubscribe:
+			if b := h.route(packetGroup(packet)); b != nil {
+				b.handleSubscribe(packet, from)
+			}
+		case protocol.PacketTypeUnsubscribe:
+			if b := h.route(packetGroup(packet)); b != nil {
+				b.handleUnsubscribe(packet, from)
+			}
+		case protocol.PacketTypeSignalReport:
+			if b := h.route(packetGroup(packet)); b != nil {
+				b.handleSignalReport(packet, from)
+			}
+		case protocol.PacketTypeHeartbeat:
+			h.handleHeartbeat(packet, from)
+		case protocol.PacketTypeDiscoveryReq:
+			// Every stream lists all groups of the shared manager
+			if b := h.route(""); b != nil {
+				b.handleDiscoveryRequest(packet, from)
+			}
+		}
+	}
+}
+
+// route returns the stream for a group, or the default stream for packets
+// without a group and for unknown groups (which it then rejects)
+func (h *Host) route(group string) *Broadcaster {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if b, ok := h.streams[group]; ok {
+		return b
+	}
+	return h.streams[h.defaultGroup]
+}
+
+// handleHeartbeat refreshes a listener in every group it joined
+// Heartbeats carry no group: one stream updates the shared manager,
+// the others only their legacy listener maps.
+func (h *Host) handleHeartbeat(packet *protocol.Packet, from *net.UDPAddr) {
+	hb, err := protocol.UnmarshalHeartbeat(packet.Payload)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to unmarshal heartbeat: %v\n", err)
+		return
+	}
+
+	listenerIP := protocol.BytesToIPv6(hb.ListenerIPv6)
+	if !sentFrom(from, listenerIP, hb.ListenerPort) {
+		return
+	}
+
+	first := h.route("")
+	if first == nil {
+		return
+	}
+	first.handleHeartbeat(packet, from)
+	for _, b := range h.Streams() {
+		if b != first {
+			b.touchListener(listenerIP)
+		}
+	}
+}
+
+// streamFor returns the broadcaster sending a group: itself, a sibling
+// stream on the same station host, or nil if the group isn't sent here
+func (b *Broadcaster) streamFor(group string) *Broadcaster {
+	if b.host != nil {
+		stream, _ := b.host.Stream(group)
+		return stream
+	}
+	if group == b.group {
+		return b
+	}
+	return nil
+}
+
+// packetGroup extracts the group a listener packet is about ("" = none)
+func packetGroup(packet *protocol.Packet) string {
+	switch packet.Type {
+	case protocol.PacketTypeSubscribe:
+		if sub, err := protocol.UnmarshalSubscribe(packet.Payload); err == nil {
+			return protocol.GetGroupString(sub.Group)
+		}
+	case protocol.PacketTypeUnsubscribe:
+		if unsub, err := protocol.UnmarshalUnsubscribe(packet.Payload); err == nil {
+			return protocol.GetGroupString(unsub.Group)
+		}
+	case protocol.PacketTypeSignalReport:
+		if sr, err := protocol.UnmarshalSignalReport(packet.Payload); err == nil {
+			return protocol.GetGroupString(sr.Group)
+		}
+	}
+	return ""
+}
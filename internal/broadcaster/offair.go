@@ -0,0 +1,59 @@
+package broadcaster
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/meshradio/meshradio/pkg/protocol"
+)
+
+// sourceEndedGrace is how long the broadcaster waits for a new source after
+// the current one ran out before telling listeners it is off air. Track
+// changes happen well within it and go unnoticed.
+const sourceEndedGrace = time.Second
+
+// StopWith stops broadcasting and tells the listeners why, and when the
+// station expects to be back (zero = unknown). Stop is StopWith(OffAirShutdown).
+func (b *Broadcaster) StopWith(reason uint8, backAt time.Time) error {
+	if b.IsRunning() {
+		b.signOff(reason, backAt)
+	}
+	return b.stop()
+}
+
+// signOff sends an OFF-AIR packet to every subscriber
+// It goes out unicast like metadata, so listeners on native multicast get it too.
+// A relay passes on the origin's OFF-AIR with Forward; when the relay itself
+// stops it signs off under its own callsign, so its listeners know the
+// repeater went away rather than waiting for the station.
+func (b *Broadcaster) signOff(reason uint8, backAt time.Time) {
+	payload := &protocol.OffAirPayload{
+		Reason: reason,
+		Group:  protocol.StringToGroup(b.group),
+	}
+	if !backAt.IsZero() {
+		payload.BackAt = uint64(backAt.Unix())
+	}
+
+	var ipv6Bytes [16]byte
+	copy(ipv6Bytes[:], b.ipv6.To16())
+
+	packet := protocol.NewPacket(
+		protocol.PacketTypeOffAir,
+		ipv6Bytes,
+		b.callsign,
+		protocol.MarshalOffAir(payload),
+	)
+	packet.StreamID = b.streamID
+	packet.SetPriority(b.priority)
+	b.sign(packet)
+
+	subscribers := b.subManager.GetSubscribersForSource(b.group, b.ipv6)
+	for _, sub := range subscribers {
+		if err := b.transport.Send(packet, sub.IPv6, sub.Port); err != nil {
+			fmt.Printf("⚠️  Failed to send off-air to %s: %v\n", sub.Callsign, err)
+		}
+	}
+
+	fmt.Printf("📴 Off air (%s), told %d listeners\n", protocol.OffAirReasonString(reason), len(subscribers))
+}
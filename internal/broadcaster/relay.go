@@ -0,0 +1,50 @@
+package broadcaster
+
+import (
+	"errors"
+	"net"
+
+	"github.com/meshradio/meshradio/pkg/protocol"
+)
+
+// ErrHopLimit is returned by Forward for packets that already passed
+// protocol.MaxHops relays, which means relays are forwarding in a loop
+var ErrHopLimit = errors.New("hop limit reached")
+
+// Forward re-sends a packet received from an upstream station to this
+// broadcaster's subscribers (relay mode)
+// The packet is passed on as is: callsign, priority, stream id, encryption
+// and signature stay the origin's, only the hop count goes up.
+func (b *Broadcaster) Forward(packet *protocol.Packet) error {
+	if !b.IsRunning() {
+		return errors.New("broadcaster not running")
+	}
+	if packet.HopCount >= protocol.MaxHops {
+		return ErrHopLimit
+	}
+	packet.HopCount++
+
+	subscribers := b.subManager.GetSubscribersForSource(b.group, b.ipv6)
+
+	// Only audio goes to the native multicast group, anything else is unicast
+	if packet.Type == protocol.PacketTypeAudio {
+		b.fanOut(packet, subscribers)
+		return nil
+	}
+	if len(subscribers) == 0 {
+		return nil
+	}
+
+	targets := make([]*net.UDPAddr, len(subscribers))
+	for i, sub := range subscribers {
+		targets[i] = &net.UDPAddr{IP: sub.IPv6, Port: sub.Port}
+	}
+	failed, err := b.transport.SendBatch(packet, targets)
+	if err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		b.recordSendErrors(failed)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package broadcaster
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/meshradio/meshradio/pkg/protocol"
+)
+
+// ListenerReport aggregates the signal reports received from one subscriber
+type ListenerReport struct {
+	Callsign        string
+	IPv6            net.IP
+	Port            uint16
+	Group           string
+	PacketsReceived uint64        // Total since the first report
+	PacketsLost     uint64        // Total since the first report
+	DecodeErrors    uint64        // Total since the first report
+	LossRate        float64       // Latest interval, 0-1
+	Jitter          time.Duration // Latest interval
+	BufferLevel     int           // Frames queued for playout at the listener
+	BufferCapacity  int
+	Quality         uint8  // 0-100, latest interval
+	SendErrors      uint64 // Fan-out sends to this subscriber that failed
+	Reports         int
+	FirstReport     time.Time
+	LastReport      time.Time
+}
+
+// reportKey identifies a subscriber in the reports map
+func reportKey(ipv6 net.IP, port uint16) string {
+	return fmt.Sprintf("%s:%d", ipv6.String(), port)
+}
+
+// handleSignalReport folds a listener's reception report into its aggregate
+func (b *Broadcaster) handleSignalReport(packet *protocol.Packet, from *net.UDPAddr) {
+	sr, err := protocol.UnmarshalSignalReport(packet.Payload)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to unmarshal signal report: %v\n", err)
+		return
+	}
+
+	listenerIP := protocol.BytesToIPv6(sr.ListenerIPv6)
+	group := protocol.GetGroupString(sr.Group)
+	if group == "" {
+		group = b.group
+	}
+
+	// Only track current subscribers, reporting for themselves
+	if !sentFrom(from, listenerIP, sr.ListenerPort) {
+		return
+	}
+	if !b.hasSubscriber(group, listenerIP, int(sr.ListenerPort)) {
+		return
+	}
+
+	now := time.Now()
+	key := reportKey(listenerIP, sr.ListenerPort)
+
+	b.reportsMu.Lock()
+	defer b.reportsMu.Unlock()
+
+	r, ok := b.reports[key]
+	if !ok {
+		r = &ListenerReport{
+			IPv6:        listenerIP,
+			Port:        sr.ListenerPort,
+			FirstReport: now,
+		}
+		b.reports[key] = r
+	}
+
+	r.Callsign = packet.GetCallsign()
+	r.Group = group
+	r.PacketsReceived += uint64(sr.PacketsReceived)
+	r.PacketsLost += uint64(sr.PacketsLost)
+	r.DecodeErrors += uint64(sr.DecodeErrors)
+	r.LossRate = 0
+	if expected := sr.PacketsReceived + sr.PacketsLost; expected > 0 {
+		r.LossRate = float64(sr.PacketsLost) / float64(expected)
+	}
+	r.Jitter = sr.JitterDuration()
+	r.BufferLevel = int(sr.BufferLevel)
+	r.BufferCapacity = int(sr.BufferCapacity)
+	r.Quality = sr.Quality
+	r.Reports++
+	r.LastReport = now
+
+	if r.LossRate > 0.1 {
+		fmt.Printf("📉 %s reports %.0f%% loss (jitter %v)\n", r.Callsign, r.LossRate*100, r.Jitter)
+	}
+}
+
+// removeReport drops a subscriber's reports (on unsubscribe)
+func (b *Broadcaster) removeReport(ipv6 net.IP, port uint16) {
+	key := reportKey(ipv6, port)
+
+	b.reportsMu.Lock()
+	delete(b.reports, key)
+	delete(b.sendErrors, key)
+	b.reportsMu.Unlock()
+}
+
+// pruneReports drops reports from subscribers that stopped reporting,
+// and send error counts of subscribers that are gone
+func (b *Broadcaster) pruneReports(timeout time.Duration) {
+	current := make(map[string]bool)
+	for _, sub := range b.subManager.GetSubscribersForSource(b.group, b.ipv6) {
+		current[reportKey(sub.IPv6, uint16(sub.Port))] = true
+	}
+
+	b.reportsMu.Lock()
+	defer b.reportsMu.Unlock()
+
+	for key, r := range b.reports {
+		if time.Since(r.LastReport) > timeout {
+			delete(b.reports, key)
+		}
+	}
+	for key := range b.sendErrors {
+		if !current[key] {
+			delete(b.sendErrors, key)
+		}
+	}
+}
+
+// GetListenerReports returns a snapshot of per-subscriber reception, sorted by callsign
+func (b *Broadcaster) GetListenerReports() []ListenerReport {
+	b.reportsMu.Lock()
+	defer b.reportsMu.Unlock()
+
+	reports := make([]ListenerReport, 0, len(b.reports))
+	for key, r := range b.reports {
+		report := *r
+		report.SendErrors = b.sendErrors[key]
+		reports = append(reports, report)
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].Callsign != reports[j].Callsign {
+			return reports[i].Callsign < reports[j].Callsign
+		}
+		return reports[i].Port < reports[j].Port
+	})
+
+	return reports
+}
@@ -1,6 +1,8 @@
 package listener
 
 import (
+	"crypto/ed25519"
+	"errors"
 	"fmt"
 	"net"
 	"sync"
@@ -9,10 +11,98 @@ import (
 
 	"github.com/meshradio/meshradio/pkg/audio"
 	"github.com/meshradio/meshradio/pkg/emergency"
+	"github.com/meshradio/meshradio/pkg/encryption"
 	"github.com/meshradio/meshradio/pkg/network"
 	"github.com/meshradio/meshradio/pkg/protocol"
+	"github.com/meshradio/meshradio/pkg/signing"
 )
 
+// Defaults for the SUBSCRIBE / SUBSCRIBE-ACK handshake
+const (
+	DefaultSubscribeTimeout = 2 * time.Second
+	DefaultSubscribeRetries = 3
+)
+
+// ErrNoSubscribeAck is returned when the broadcaster never answers a SUBSCRIBE
+var ErrNoSubscribeAck = errors.New("no SUBSCRIBE-ACK from broadcaster")
+
+// SubscribeRejectedError is returned when the broadcaster refuses a subscription
+type SubscribeRejectedError struct {
+	Group  string
+	Reason uint8 // protocol.Reject* code
+}
+
+// Error implements the error interface
+func (e *SubscribeRejectedError) Error() string {
+	return fmt.Sprintf("subscription to group '%s' rejected: %s",
+		e.Group, protocol.RejectReasonString(e.Reason))
+}
+
+// DefaultSignalReportInterval is how often reception reports are sent to the broadcaster
+const DefaultSignalReportInterval = 5 * time.Second
+
+// alertFreshness is how far the signed timestamp of an emergency alert may be
+// from our clock; older alerts are replays
+const alertFreshness = 30 * time.Second
+
+// alertWarnInterval limits how often refused alerts are reported
+const alertWarnInterval = 10 * time.Second
+
+// beaconLossFactor is how many beacon intervals may pass silently before
+// the station is considered gone
+const beaconLossFactor = 3
+
+// StationInfo describes the tuned station as advertised by its beacons
+type StationInfo struct {
+	Callsign      string
+	Group         string
+	Priority      uint8
+	CodecType     uint8
+	SampleRate    int // Hz
+	Channels      int
+	Bitrate       int // bps
+	ListenerCount int
+	Uptime        time.Duration
+	Interval      time.Duration // Advertised beacon interval
+	LastBeacon    time.Time     // Zero if no beacon received yet
+	Lost          bool          // No beacon for beaconLossFactor intervals
+}
+
+// ReceptionStats summarizes audio reception since the listener started
+type ReceptionStats struct {
+	PacketsReceived uint64
+	PacketsLost     uint64 // Detected from sequence number gaps
+	DecodeErrors    uint64
+	Jitter          time.Duration // Interarrival jitter estimate
+	BufferLevel     int           // Frames queued for playout
+	BufferCapacity  int
+	Quality         uint8 // 0-100, from the latest report interval
+	Silent          bool  // Station is in a DTX pause, only comfort noise keepalives arrive
+}
+
+// receptionCounters tracks audio arrivals for signal reports
+type receptionCounters struct {
+	haveSeq       bool
+	lastSeq       uint32
+	streamID      uint32 // v2 stream id of the tracked stream
+	seen          uint64 // Bit i set = lastSeq-i arrived (duplicate detection)
+	lastArrival   time.Time
+	lastSendTime  int64   // Send time of the previous packet (microseconds, stream clock)
+	jitter        float64 // Microseconds
+	silent        bool    // Latest packet was a DTX comfort noise keepalive
+
+	// Current report interval
+	received     uint32
+	lost         uint32
+	decodeErrors uint32
+
+	// Totals
+	totalReceived uint64
+	totalLost     uint64
+	totalErrors   uint64
+	quality       uint8
+}
+
 // Listener receives and plays audio streams
 type Listener struct {
 	callsign    string
@@ -22,7 +112,7 @@ type Listener struct {
 	targetPort  int
 	group       string  // Multicast group (e.g., "emergency", "community")
 	ssmSource   net.IP  // SSM source (nil = regular multicast)
-	transport   *network.Transport
+	transport   network.Transport
 	audioOut    *audio.OutputStream
 	codec       audio.Codec
 	config      audio.StreamConfig
@@ -31,20 +121,62 @@ type Listener struct {
 	stopChan    chan struct{}
 
 	// Subscription state
-	subscribed      bool
+	subscribed      atomic.Bool // Read by the heartbeat and report loops
 	lastHeartbeat   time.Time
+	ackChan         chan *protocol.SubscribeAckPayload
+	challengeChan   chan *protocol.SubscribeChallengePayload
+	leaseLost       chan struct{} // The station challenged us while subscribed: it forgot us
+	ended           error         // Why the broadcaster ended the subscription (nil = it didn't)
+	subTimeout      time.Duration
+	subRetries      int
+
+	// Lease negotiated with the broadcaster via SUBSCRIBE-ACK
+	heartbeatInterval time.Duration
+	leaseTimeout      time.Duration
 
 	// Stats
 	packetsReceived uint64
-	lastSeqNum      uint8
+	lastSeqNum      uint32
 	stationCallsign string
+	station         StationInfo
+
+	// Reception stats for signal reports
+	reception      receptionCounters
+	receptionMu    sync.Mutex
+	reportInterval time.Duration
+
+	// Now-playing metadata from the station (nil = none received)
+	metadata   *protocol.Metadata
+	metadataAt time.Time
+
+	// Set by an OFF-AIR from the station, cleared by its next audio (nil = on air)
+	offAir     *OffAirInfo
+	offAirFlag atomic.Bool
 
 	// Emergency handling (Layer 5)
 	emergencySettings emergency.EmergencySettings
 	lastPriority      uint8
+	alertWarnedAt     time.Time // Last refused alert report (decode worker only)
+	trustStore        *signing.TrustStore
+	allowUnsigned     bool
+
+	// Listener key for SUBSCRIBE signatures (nil = unsigned)
+	signingKey ed25519.PrivateKey
+
+	// Private channels
+	keyring   *encryption.Keyring
+	keyWarned map[uint8]bool // Unknown key ids already reported
+
+	// Native IPv6 multicast (nil until joined)
+	multicastCfg  *network.MulticastConfig
+	mcast         network.Transport
+	lastMulticast int64 // UnixNano of the last multicast audio packet (atomic)
+
+	// Relay mode: stream packets are handed over undecoded (nil = play them)
+	forward func(*protocol.Packet)
 
 	// Decode queue - to offload decoding from receive loop
-	decodeQueue chan *protocol.Packet
+	decodeQueue *network.PacketQueue
 }
 
 // Config holds listener configuration
@@ -57,13 +189,34 @@ type Config struct {
 	Group       string  // Multicast group (e.g., "emergency", "community")
 	SSMSource   net.IP  // SSM source (nil = regular multicast, receives from all)
 	AudioConfig audio.StreamConfig
+
+	SubscribeTimeout     time.Duration // Optional: wait per SUBSCRIBE attempt for the ack (default: 2s)
+	SubscribeRetries     int           // Optional: SUBSCRIBE attempts before giving up (default: 3)
+	SignalReportInterval time.Duration // Optional: reception report interval (default: 5s)
+
+	TrustStore          *signing.TrustStore // Optional: station keys; emergency alerts need a trusted signature
+	AllowUnsignedAlerts bool                // Optional: raise alerts for unsigned emergency packets (insecure)
+	SigningKey          ed25519.PrivateKey  // Optional: listener key, signs SUBSCRIBE so stations can match callsign rules
+
+	Keyring *encryption.Keyring // Optional: group keys for private channels
+
+	Transport network.Transport        // Optional: packet transport (default: UDP socket on LocalPort)
+	Multicast *network.MulticastConfig // Optional: join the broadcaster's native multicast group when offered
+
+	// Optional: relay mode. Audio, metadata and off-air packets from the target are passed
+	// here as received (still signed and encrypted) instead of being played.
+	Forward func(*protocol.Packet)
 }
 
 // New creates a new listener
 func New(cfg Config) (*Listener, error) {
-	transport, err := network.NewTransport(cfg.LocalPort)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create transport: %w", err)
+	transport := cfg.Transport
+	if transport == nil {
+		udp, err := network.NewTransport(cfg.LocalPort)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create transport: %w", err)
+		}
+		transport = udp
 	}
 
 	audioOut := audio.NewOutputStream(cfg.AudioConfig)
@@ -85,10 +238,30 @@ func New(cfg Config) (*Listener, error) {
 		group = "default"
 	}
 
+	// Subscribe handshake defaults
+	subTimeout := cfg.SubscribeTimeout
+	if subTimeout <= 0 {
+		subTimeout = DefaultSubscribeTimeout
+	}
+	subRetries := cfg.SubscribeRetries
+	if subRetries <= 0 {
+		subRetries = DefaultSubscribeRetries
+	}
+
+	reportInterval := cfg.SignalReportInterval
+	if reportInterval <= 0 {
+		reportInterval = DefaultSignalReportInterval
+	}
+
+	trustStore := cfg.TrustStore
+	if trustStore == nil {
+		trustStore = signing.NewTrustStore()
+	}
+
 	return &Listener{
 		callsign:          cfg.Callsign,
 		localIPv6:         cfg.LocalIPv6,
-		localPort:         cfg.LocalPort,
+		localPort:         transport.LocalAddr().Port, // Actual port when LocalPort is 0
 		targetIPv6:        cfg.TargetIPv6,
 		targetPort:        cfg.TargetPort,
 		group:             group,
@@ -98,8 +271,21 @@ func New(cfg Config) (*Listener, error) {
 		codec:             codec,
 		config:            cfg.AudioConfig,
 		stopChan:          make(chan struct{}),
+		ackChan:           make(chan *protocol.SubscribeAckPayload, 1),
+		challengeChan:     make(chan *protocol.SubscribeChallengePayload, 1),
+		leaseLost:         make(chan struct{}, 1),
+		subTimeout:        subTimeout,
+		subRetries:        subRetries,
+		reportInterval:    reportInterval,
 		emergencySettings: emergency.DefaultSettings(),
-		decodeQueue:       make(chan *protocol.Packet, 100), // Buffer 100 packets for decoding
+		trustStore:        trustStore,
+		allowUnsigned:     cfg.AllowUnsignedAlerts,
+		signingKey:        cfg.SigningKey,
+		keyring:           cfg.Keyring,
+		keyWarned:         make(map[uint8]bool),
+		multicastCfg:      cfg.Multicast,
+		forward:           cfg.Forward,
+		decodeQueue:       network.NewPacketQueue(100), // Buffer 100 normal audio packets for decoding
 	}, nil
 }
 
@@ -118,25 +304,34 @@ func (l *Listener) Start() error {
 		return fmt.Errorf("failed to start transport: %w", err)
 	}
 
-	// Start audio output
-	if err := l.audioOut.Start(); err != nil {
-		return fmt.Errorf("failed to start audio output: %w", err)
-	}
-
-	// Send SUBSCRIBE packet to broadcaster
-	if err := l.subscribe(); err != nil {
-		return fmt.Errorf("failed to subscribe: %w", err)
+	// Start audio output (a relay plays nothing)
+	if l.forward == nil {
+		if err := l.audioOut.Start(); err != nil {
+			return fmt.Errorf("failed to start audio output: %w", err)
+		}
 	}
 
 	// Start decode worker (single goroutine for thread-safe codec access)
 	go l.decodeWorker()
 
-	// Start receive loop
+	// Start receive loop (also delivers the SUBSCRIBE-ACK)
 	go l.receiveLoop()
 
+	// Send SUBSCRIBE packet to broadcaster and wait for the lease
+	if err := l.subscribe(); err != nil {
+		l.Stop()
+		return fmt.Errorf("failed to subscribe: %w", err)
+	}
+
 	// Start heartbeat loop
 	go l.heartbeatLoop()
 
+	// Watch for beacon loss
+	go l.stationMonitor()
+
+	// Report reception quality to the broadcaster
+	go l.signalReportLoop()
+
 	fmt.Printf("Subscribed to %s:%d\n", l.targetIPv6.String(), l.targetPort)
 
 	return nil
@@ -152,10 +347,24 @@ func (l *Listener) Stop() error {
 	}
 
 	l.running = false
+
+	// Tell the broadcaster we're leaving so it stops sending immediately
+	if l.subscribed.Load() {
+		if err := l.unsubscribe(); err != nil {
+			fmt.Printf("⚠️  Failed to send unsubscribe: %v\n", err)
+		}
+	}
+
+	if l.mcast != nil {
+		l.mcast.Stop() // Leaves the multicast group
+	}
+
 	close(l.stopChan)
-	close(l.decodeQueue) // Stop decode worker
+	l.decodeQueue.Close() // Stop decode worker
 
-	l.audioOut.Stop()
+	if l.forward == nil {
+		l.audioOut.Stop()
+	}
 	l.transport.Stop()
 
 	return nil
@@ -174,10 +383,11 @@ func (l *Listener) receiveLoop() {
 		}
 
 		// Receive packet
-		packet, err := l.transport.Receive()
+		packet, from, err := l.transport.Receive()
 		if err != nil {
 			// Warn if we haven't received packets for >5 seconds
-			if time.Since(lastReceiveTime) > 5*time.Second && !noPacketWarned {
+			// (unless the station said it went off air)
+			if time.Since(lastReceiveTime) > 5*time.Second && !noPacketWarned && !l.offAirFlag.Load() {
 				fmt.Printf("⚠️  No packets received for >5s (last: %v ago)\n", time.Since(lastReceiveTime))
 				noPacketWarned = true
 			}
@@ -190,29 +400,69 @@ func (l *Listener) receiveLoop() {
 		lastReceiveTime = time.Now()
 		noPacketWarned = false
 
+		// A relay re-sends stream packets to all its listeners: never take them
+		// from anyone but the upstream station
+		if l.forward != nil && !l.fromTarget(from) &&
+			(packet.Type == protocol.PacketTypeAudio || packet.Type == protocol.PacketTypeMetadata) {
+			continue
+		}
+
 		// Handle different packet types
 		switch packet.Type {
 		case protocol.PacketTypeAudio:
-			// Queue packet for decoding (non-blocking with buffered channel)
-			// This allows receive loop to drain network socket quickly
-			select {
-			case l.decodeQueue <- packet:
-			default:
-				// Queue full, drop packet (should rarely happen with 100 buffer)
-				fmt.Printf("⚠️  Decode queue full, dropping audio packet\n")
-			}
+			l.queueAudio(packet, lastReceiveTime)
 		case protocol.PacketTypeBeacon:
-			l.handleBeacon(packet)
+			// Only the station we subscribed to may set the station info
+			if l.fromTarget(from) {
+				l.handleBeacon(packet)
+			}
 		case protocol.PacketTypeMetadata:
 			l.handleMetadata(packet)
+		case protocol.PacketTypeSubscribeAck:
+			if l.fromTarget(from) {
+				l.handleSubscribeAck(packet)
+			}
+		case protocol.PacketTypeSubscribeChallenge:
+			if l.fromTarget(from) {
+				l.handleSubscribeChallenge(packet)
+			}
+		case protocol.PacketTypeOffAir:
+			if l.fromTarget(from) {
+				l.handleOffAir(packet)
+			}
 		}
 	}
 }
 
+// queueAudio records an audio packet's arrival and hands it to the decoder
+// Duplicates (e.g. the unicast and multicast copy of a frame) are dropped here.
+func (l *Listener) queueAudio(packet *protocol.Packet, arrival time.Time) {
+	if !l.recordArrival(packet, arrival) {
+		return
+	}
+	l.backOnAir(packet)
+
+	// Queue packet for decoding (non-blocking, emergency audio goes first)
+	// This allows receive loop to drain network socket quickly
+	if l.decodeQueue.Push(packet, nil) {
+		// Queue full, oldest packet of the class shed (should rarely happen with 100 buffer)
+		fmt.Printf("⚠️  Decode queue full, dropping audio packet\n")
+	}
+}
+
 // decodeWorker processes audio packets from the decode queue
 // Runs in a single goroutine to ensure thread-safe codec access and packet ordering
 func (l *Listener) decodeWorker() {
-	for packet := range l.decodeQueue {
+	for {
+		packet, _, ok := l.decodeQueue.Pop()
+		if !ok {
+			return
+		}
+		if l.forward != nil {
+			atomic.AddUint64(&l.packetsReceived, 1)
+			l.forward(packet)
+			continue
+		}
 		l.handleAudioPacket(packet)
 	}
 }
@@ -226,14 +476,20 @@ func (l *Listener) handleAudioPacket(packet *protocol.Packet) {
 	priority := packet.GetPriority()
 
 	// Check for priority change (emergency broadcast)
-	if priority != l.lastPriority {
-		l.handlePriorityChange(packet, priority)
+	// A refused alert leaves lastPriority alone, so the genuine one still raises it
+	if priority != l.lastPriority && l.handlePriorityChange(packet, priority) {
 		l.lastPriority = priority
 	}
 
+	// Private channel: decrypt the payload (after the priority check, which verifies the signature)
+	if !l.decrypt(packet) {
+		return
+	}
+
 	// Parse audio payload
 	audioPacket, err := protocol.UnmarshalAudioPayload(packet.Payload)
 	if err != nil {
+		l.recordDecodeError()
 		fmt.Printf("Failed to unmarshal audio: %v\n", err)
 		return
 	}
@@ -241,6 +497,7 @@ func (l *Listener) handleAudioPacket(packet *protocol.Packet) {
 	// Decode audio
 	pcm, err := l.codec.Decode(audioPacket.AudioData)
 	if err != nil {
+		l.recordDecodeError()
 		fmt.Printf("Failed to decode audio: %v\n", err)
 		return
 	}
@@ -262,18 +519,142 @@ func (l *Listener) handleAudioPacket(packet *protocol.Packet) {
 	l.lastSeqNum = packet.SequenceNum
 }
 
+// recordArrival updates loss and jitter counters for an audio packet
+// Returns false for a duplicate of a recently seen packet.
+func (l *Listener) recordArrival(packet *protocol.Packet, arrival time.Time) bool {
+	l.receptionMu.Lock()
+	defer l.receptionMu.Unlock()
+
+	rc := &l.reception
+
+	// v2 packets are timed by the media clock, v1 only has the sender's wall clock
+	sendTime := packet.Timestamp * 1000
+	if packet.Version >= 2 && l.config.SampleRate > 0 {
+		sendTime = int64(packet.MediaTimestamp) * 1000000 / int64(l.config.SampleRate)
+	}
+
+	// A new stream id means the broadcaster restarted, start tracking afresh
+	if rc.haveSeq && packet.StreamID != rc.streamID {
+		rc.haveSeq = false
+	}
+
+	if rc.haveSeq {
+		// Late or duplicate packets (delta <= 0) are not loss
+		delta := packet.SequenceDelta(rc.lastSeq)
+		switch {
+		case delta > 0:
+			rc.lost += uint32(delta - 1)
+			rc.totalLost += uint64(delta - 1)
+			rc.lastSeq = packet.SequenceNum
+			if delta < 64 {
+				rc.seen = rc.seen<<uint(delta) | 1
+			} else {
+				rc.seen = 1
+			}
+		case -delta < 64:
+			bit := uint64(1) << uint(-delta)
+			if rc.seen&bit != 0 {
+				return false
+			}
+			rc.seen |= bit
+		}
+
+		// RFC 3550 interarrival jitter: J += (|D| - J) / 16
+		transit := arrival.Sub(rc.lastArrival).Microseconds() - (sendTime - rc.lastSendTime)
+		if transit < 0 {
+			transit = -transit
+		}
+		rc.jitter += (float64(transit) - rc.jitter) / 16
+	} else {
+		rc.haveSeq = true
+		rc.lastSeq = packet.SequenceNum
+		rc.streamID = packet.StreamID
+		rc.seen = 1
+	}
+
+	// DTX gaps carry no sequence numbers, so they are never counted as loss
+	if rc.lastSeq == packet.SequenceNum {
+		rc.silent = packet.Flags&protocol.FlagComfortNoise != 0
+	}
+
+	rc.received++
+	rc.totalReceived++
+	rc.lastArrival = arrival
+	rc.lastSendTime = sendTime
+	return true
+}
+
+// decrypt opens an encrypted packet in place. Returns false if the packet must be
+// dropped: unknown keys are reported once per key id, corrupt packets count as decode errors.
+// On a group we hold a key for, clear packets are dropped too: anyone could inject them.
+func (l *Listener) decrypt(packet *protocol.Packet) bool {
+	if packet.Flags&protocol.FlagEncrypted == 0 {
+		if l.keyring.IsPrivate(l.group) {
+			l.recordDecodeError()
+			return false
+		}
+		return true
+	}
+
+	err := l.keyring.Open(l.group, packet)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, encryption.ErrUnknownKey):
+		keyID := encryption.KeyID(packet)
+		l.mu.Lock()
+		warned := l.keyWarned[keyID]
+		l.keyWarned[keyID] = true
+		l.mu.Unlock()
+		if !warned {
+			fmt.Printf("🔒 Group '%s' is private: no key %d, dropping encrypted packets\n", l.group, keyID)
+		}
+	default:
+		l.recordDecodeError()
+	}
+	return false
+}
+
+// recordDecodeError counts an audio packet that could not be decoded
+func (l *Listener) recordDecodeError() {
+	l.receptionMu.Lock()
+	l.reception.decodeErrors++
+	l.reception.totalErrors++
+	l.receptionMu.Unlock()
+}
+
 // handlePriorityChange handles priority level changes
-func (l *Listener) handlePriorityChange(packet *protocol.Packet, priority uint8) {
+// Returns false if an emergency alert was refused (untrusted or stale).
+func (l *Listener) handlePriorityChange(packet *protocol.Packet, priority uint8) bool {
 	p := emergency.Priority(priority)
 
 	// Only log significant priority changes
 	if priority < uint8(emergency.PriorityHigh) {
-		return
+		return true
 	}
 
 	sourceIPv6 := protocol.BytesToIPv6(packet.SourceIPv6)
 	callsign := packet.GetCallsign()
 
+	// Anyone can forge a callsign, only alert for emergencies signed by a trusted station key
+	if priority >= uint8(emergency.PriorityEmergency) {
+		verdict := l.trustStore.Check(packet)
+		if verdict != signing.Trusted && !(verdict == signing.Unsigned && l.allowUnsigned) {
+			l.warnRejectedAlert(fmt.Sprintf("%s %s broadcast claiming to be %s (%s)",
+				verdict, p.String(), callsign, sourceIPv6))
+			return false
+		}
+
+		// A captured alert replayed later carries its old signed timestamp
+		if verdict == signing.Trusted {
+			if age := time.Since(time.UnixMilli(packet.Timestamp)); age > alertFreshness || age < -alertFreshness {
+				l.warnRejectedAlert(fmt.Sprintf("stale %s broadcast from %s (%s), signed %v ago",
+					p.String(), callsign, sourceIPv6, age.Round(time.Second)))
+				return false
+			}
+		}
+	}
+
 	switch {
 	case priority >= uint8(emergency.PriorityCritical):
 		fmt.Printf("\n🚨 CRITICAL EMERGENCY BROADCAST from %s (%s)\n",
@@ -290,29 +671,227 @@ func (l *Listener) handlePriorityChange(packet *protocol.Packet, priority uint8)
 			callsign, sourceIPv6)
 		fmt.Printf("   Priority: %s | Group: %s\n\n", p.String(), l.group)
 	}
+
+	return true
+}
+
+// warnRejectedAlert reports a refused emergency alert, at most once per
+// alertWarnInterval: a forged stream would otherwise log every frame
+func (l *Listener) warnRejectedAlert(msg string) {
+	if time.Since(l.alertWarnedAt) < alertWarnInterval {
+		return
+	}
+	l.alertWarnedAt = time.Now()
+	fmt.Printf("\n🚫 Ignoring %s\n\n", msg)
 }
 
 // handleBeacon processes a beacon packet
 func (l *Listener) handleBeacon(packet *protocol.Packet) {
 	callsign := packet.GetCallsign()
+
+	beacon, err := protocol.UnmarshalBeacon(packet.Payload)
+	if err != nil {
+		fmt.Printf("Invalid beacon from %s: %v\n", callsign, err)
+		return
+	}
+
+	l.mu.Lock()
 	if l.stationCallsign == "" {
-		l.stationCallsign = callsign
 		fmt.Printf("Connected to station: %s\n", callsign)
 	}
+	l.stationCallsign = callsign
+	if l.station.Lost {
+		fmt.Printf("📶 Station %s is back (beacon received)\n", callsign)
+	}
+	l.station = StationInfo{
+		Callsign:      callsign,
+		Group:         protocol.GetGroupString(beacon.Group),
+		Priority:      beacon.Priority,
+		CodecType:     beacon.CodecType,
+		SampleRate:    int(beacon.SampleRate) * 1000,
+		Channels:      int(beacon.Channels),
+		Bitrate:       int(beacon.Bitrate) * 1000,
+		ListenerCount: int(beacon.ListenerCount),
+		Uptime:        time.Duration(beacon.Uptime) * time.Second,
+		Interval:      beacon.IntervalDuration(),
+		LastBeacon:    time.Now(),
+	}
+	l.mu.Unlock()
+}
+
+// stationMonitor flags the station as lost when its beacons stop.
+// This is independent of audio gaps, which may be caused by silence or loss.
+func (l *Listener) stationMonitor() {
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			l.mu.Lock()
+			st := &l.station
+			// A station that signed off stops beaconing on purpose
+			if !st.LastBeacon.IsZero() && !st.Lost && st.Interval > 0 && l.offAir == nil {
+				silence := time.Since(st.LastBeacon)
+				if silence > beaconLossFactor*st.Interval {
+					st.Lost = true
+					fmt.Printf("📴 Station %s lost (no beacon for %v)\n",
+						st.Callsign, silence.Round(time.Second))
+				}
+			}
+			l.mu.Unlock()
+
+		case <-l.stopChan:
+			return
+		}
+	}
+}
+
+// handleSubscribeAck hands a SUBSCRIBE-ACK to the waiting subscribe call
+func (l *Listener) handleSubscribeAck(packet *protocol.Packet) {
+	ack, err := protocol.UnmarshalSubscribeAck(packet.Payload)
+	if err != nil {
+		fmt.Printf("Invalid SUBSCRIBE-ACK: %v\n", err)
+		return
+	}
+
+	// A rejection while subscribed: the broadcaster kicked or banned us
+	if ack.Result != protocol.SubscribeAccepted && l.subscribed.CompareAndSwap(true, false) {
+		l.mu.Lock()
+		l.ended = &SubscribeRejectedError{Group: l.group, Reason: ack.Result}
+		l.mu.Unlock()
+		fmt.Printf("❌ Broadcaster ended the subscription to group '%s': %s\n",
+			l.group, protocol.RejectReasonString(ack.Result))
+		return
+	}
+
+	select {
+	case l.ackChan <- ack:
+	default:
+		// Nobody waiting (duplicate ack from a retried SUBSCRIBE)
+	}
+}
+
+// SubscriptionEnded returns why the broadcaster ended our subscription
+// (a *SubscribeRejectedError), or nil while it stands
+func (l *Listener) SubscriptionEnded() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.ended
+}
+
+// handleSubscribeChallenge hands a SUBSCRIBE challenge to the waiting subscribe call
+// While subscribed, a challenge is the station's answer to a heartbeat it has
+// no lease for: the heartbeat loop then subscribes again.
+func (l *Listener) handleSubscribeChallenge(packet *protocol.Packet) {
+	challenge, err := protocol.UnmarshalSubscribeChallenge(packet.Payload)
+	if err != nil {
+		fmt.Printf("Invalid SUBSCRIBE challenge: %v\n", err)
+		return
+	}
+
+	if l.subscribed.CompareAndSwap(true, false) {
+		select {
+		case l.leaseLost <- struct{}{}:
+		default:
+		}
+		return
+	}
+
+	select {
+	case l.challengeChan <- challenge:
+	default:
+		// Nobody waiting (not subscribing, or a challenge is already pending)
+	}
+}
+
+// fromTarget reports whether a packet came from the broadcaster we subscribed to.
+// Subscription control packets from anywhere else are forged and ignored.
+func (l *Listener) fromTarget(from *net.UDPAddr) bool {
+	return from.IP.Equal(l.targetIPv6) && from.Port == l.targetPort
 }
 
 // handleMetadata processes a metadata packet
 func (l *Listener) handleMetadata(packet *protocol.Packet) {
-	fmt.Printf("Metadata from %s: %s\n", packet.GetCallsign(), string(packet.Payload))
+	if l.forward != nil {
+		l.forward(packet)
+		return
+	}
+
+	if !l.decrypt(packet) {
+		return
+	}
+
+	md, err := protocol.UnmarshalMetadata(packet.Payload)
+	if err != nil {
+		fmt.Printf("Invalid metadata from %s: %v\n", packet.GetCallsign(), err)
+		return
+	}
+
+	l.mu.Lock()
+	changed := l.metadata == nil || l.metadata.Title != md.Title ||
+		l.metadata.Artist != md.Artist || l.metadata.StationText != md.StationText
+	l.metadata = md
+	l.metadataAt = time.Now()
+	l.mu.Unlock()
+
+	// Repeats are sent periodically, only log actual changes
+	if changed {
+		fmt.Printf("🎵 Now playing on %s: %s\n", packet.GetCallsign(), md.DisplayTitle())
+	}
 }
 
 // GetStats returns listener statistics
-func (l *Listener) GetStats() (packetsReceived uint64, lastSeq uint8, station string) {
+func (l *Listener) GetStats() (packetsReceived uint64, lastSeq uint32, station string) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	return atomic.LoadUint64(&l.packetsReceived), l.lastSeqNum, l.stationCallsign
 }
 
+// GetTransportStats returns the traffic and drop counters of the listener's socket
+func (l *Listener) GetTransportStats() network.TransportStats {
+	return l.transport.Stats()
+}
+
+// GetReceptionStats returns audio reception statistics
+func (l *Listener) GetReceptionStats() ReceptionStats {
+	queued, capacity := l.audioOut.BufferLevel()
+
+	l.receptionMu.Lock()
+	defer l.receptionMu.Unlock()
+
+	rc := &l.reception
+	return ReceptionStats{
+		PacketsReceived: rc.totalReceived,
+		PacketsLost:     rc.totalLost,
+		DecodeErrors:    rc.totalErrors,
+		Jitter:          time.Duration(rc.jitter) * time.Microsecond,
+		BufferLevel:     queued,
+		BufferCapacity:  capacity,
+		Quality:         rc.quality,
+		Silent:          rc.silent,
+	}
+}
+
+// GetStationInfo returns the station details from the latest beacon
+func (l *Listener) GetStationInfo() StationInfo {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.station
+}
+
+// GetMetadata returns the station's now-playing metadata, with Position
+// advanced to the current time
+func (l *Listener) GetMetadata() (protocol.Metadata, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.metadata == nil {
+		return protocol.Metadata{}, false
+	}
+	return l.metadata.At(time.Since(l.metadataAt)), true
+}
+
 // IsRunning returns whether the listener is running
 func (l *Listener) IsRunning() bool {
 	l.mu.Lock()
@@ -320,7 +899,8 @@ func (l *Listener) IsRunning() bool {
 	return l.running
 }
 
-// subscribe sends a SUBSCRIBE packet to the broadcaster
+// subscribe sends a SUBSCRIBE packet to the broadcaster and waits for the
+// SUBSCRIBE-ACK, retrying on timeout
 func (l *Listener) subscribe() error {
 	var ipv6Bytes [16]byte
 	copy(ipv6Bytes[:], l.localIPv6.To16())
@@ -345,40 +925,123 @@ func (l *Listener) subscribe() error {
 		SSMSource:    ssmSourceBytes,
 	}
 
+	// Forget replies to an earlier handshake
+	select {
+	case <-l.ackChan:
+	default:
+	}
+	select {
+	case <-l.challengeChan:
+	default:
+	}
+
+	multicastType := "Regular multicast"
+	if l.ssmSource != nil {
+		multicastType = fmt.Sprintf("SSM (source=%s)", l.ssmSource)
+	}
+
+	for attempt := 1; attempt <= l.subRetries; attempt++ {
+		packet := protocol.NewPacket(
+			protocol.PacketTypeSubscribe,
+			ipv6Bytes,
+			l.callsign,
+			protocol.MarshalSubscribe(subPayload),
+		)
+		if l.signingKey != nil {
+			packet.Sign(l.signingKey)
+		}
+
+		err := l.transport.Send(packet, l.targetIPv6, l.targetPort)
+		if err != nil {
+			return fmt.Errorf("failed to send subscribe: %w", err)
+		}
+
+		fmt.Printf("Sent SUBSCRIBE to %s:%d [%s] group='%s' (attempt %d/%d)\n",
+			l.targetIPv6.String(), l.targetPort, multicastType, l.group, attempt, l.subRetries)
+
+		select {
+		case ack := <-l.ackChan:
+			if ack.Result != protocol.SubscribeAccepted {
+				return &SubscribeRejectedError{Group: l.group, Reason: ack.Result}
+			}
+
+			l.heartbeatInterval = ack.HeartbeatDuration()
+			l.leaseTimeout = ack.LeaseDuration()
+			if l.heartbeatInterval <= 0 {
+				l.heartbeatInterval = 5 * time.Second // Broadcaster sent no lease, use the classic interval
+			}
+			l.subscribed.Store(true)
+			l.lastHeartbeat = time.Now()
+			l.joinMulticast(ack)
+
+			fmt.Printf("SUBSCRIBE-ACK: group='%s' heartbeat=%v lease=%v\n",
+				protocol.GetGroupString(ack.Group), l.heartbeatInterval, l.leaseTimeout)
+			return nil
+
+		case challenge := <-l.challengeChan:
+			// Return-routability check: echo the cookie to prove we receive at our address.
+			// Answering a new challenge doesn't use up an attempt.
+			if challenge.Cookie != subPayload.Cookie {
+				attempt--
+			}
+			subPayload.Cookie = challenge.Cookie
+			fmt.Printf("SUBSCRIBE challenge from %s, answering\n", l.targetIPv6)
+
+		case <-time.After(l.subTimeout):
+			// Retry
+
+		case <-l.stopChan:
+			return fmt.Errorf("listener stopped")
+		}
+	}
+
+	return ErrNoSubscribeAck
+}
+
+// unsubscribe sends an UNSUBSCRIBE packet to the broadcaster
+func (l *Listener) unsubscribe() error {
+	var ipv6Bytes [16]byte
+	copy(ipv6Bytes[:], l.localIPv6.To16())
+
+	unsubPayload := &protocol.UnsubscribePayload{
+		ListenerIPv6: ipv6Bytes,
+		ListenerPort: uint16(l.localPort),
+		Group:        protocol.StringToGroup(l.group),
+	}
+
 	packet := protocol.NewPacket(
-		protocol.PacketTypeSubscribe,
+		protocol.PacketTypeUnsubscribe,
 		ipv6Bytes,
 		l.callsign,
-		protocol.MarshalSubscribe(subPayload),
+		protocol.MarshalUnsubscribe(unsubPayload),
 	)
 
 	err := l.transport.Send(packet, l.targetIPv6, l.targetPort)
 	if err != nil {
-		return fmt.Errorf("failed to send subscribe: %w", err)
+		return fmt.Errorf("failed to send unsubscribe: %w", err)
 	}
 
-	l.subscribed = true
-	l.lastHeartbeat = time.Now()
+	l.subscribed.Store(false)
 
-	multicastType := "Regular multicast"
-	if l.ssmSource != nil {
-		multicastType = fmt.Sprintf("SSM (source=%s)", l.ssmSource)
-	}
-	fmt.Printf("Sent SUBSCRIBE to %s:%d [%s] group='%s'\n",
-		l.targetIPv6.String(), l.targetPort, multicastType, l.group)
+	fmt.Printf("Sent UNSUBSCRIBE to %s:%d group='%s'\n",
+		l.targetIPv6.String(), l.targetPort, l.group)
 
 	return nil
 }
 
 // heartbeatLoop sends periodic heartbeats to broadcaster
 func (l *Listener) heartbeatLoop() {
-	ticker := time.NewTicker(5 * time.Second)
+	ticker := time.NewTicker(l.heartbeatInterval)
 	defer ticker.Stop()
 
 	for {
 		select {
+		case <-l.leaseLost:
+			l.resubscribe()
+
 		case <-ticker.C:
-			if !l.subscribed {
+			if !l.subscribed.Load() {
+				l.resubscribe() // Retry one that failed, unless we were kicked or stopped
 				continue
 			}
 
@@ -388,6 +1051,11 @@ func (l *Listener) heartbeatLoop() {
 			hbPayload := &protocol.HeartbeatPayload{
 				ListenerIPv6: ipv6Bytes,
 				Timestamp:    uint64(time.Now().Unix()),
+				ListenerPort: uint16(l.localPort),
+			}
+			// Tell the broadcaster it can stop the unicast copy
+			if l.receivingNative() {
+				hbPayload.Flags |= protocol.HeartbeatFlagNativeMulticast
 			}
 
 			packet := protocol.NewPacket(
@@ -409,3 +1077,88 @@ func (l *Listener) heartbeatLoop() {
 		}
 	}
 }
+
+// resubscribe subscribes again after the station dropped our lease, e.g. when
+// an outage outlasted it or the station restarted. It runs on the heartbeat
+// loop, which pauses meanwhile.
+func (l *Listener) resubscribe() {
+	l.mu.Lock()
+	done := !l.running || l.ended != nil
+	l.mu.Unlock()
+	if done {
+		return
+	}
+
+	fmt.Printf("🔄 Station %s no longer has our subscription, subscribing again\n", l.targetIPv6)
+
+	err := l.subscribe()
+	var rejected *SubscribeRejectedError
+	switch {
+	case errors.As(err, &rejected):
+		l.mu.Lock()
+		l.ended = rejected
+		l.mu.Unlock()
+		fmt.Printf("❌ Subscription to group '%s' refused: %s\n", l.group, protocol.RejectReasonString(rejected.Reason))
+	case err != nil:
+		fmt.Printf("⚠️  Failed to subscribe again, retrying: %v\n", err)
+	}
+}
+
+// signalReportLoop sends periodic reception reports to the broadcaster
+func (l *Listener) signalReportLoop() {
+	ticker := time.NewTicker(l.reportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if !l.subscribed.Load() {
+				continue
+			}
+			if err := l.sendSignalReport(); err != nil {
+				fmt.Printf("⚠️  Failed to send signal report: %v\n", err)
+			}
+
+		case <-l.stopChan:
+			return
+		}
+	}
+}
+
+// sendSignalReport sends the counters for the current interval and resets them
+func (l *Listener) sendSignalReport() error {
+	var ipv6Bytes [16]byte
+	copy(ipv6Bytes[:], l.localIPv6.To16())
+
+	queued, capacity := l.audioOut.BufferLevel()
+
+	l.receptionMu.Lock()
+	rc := &l.reception
+	quality := protocol.SignalQuality(rc.received, rc.lost, rc.decodeErrors)
+	report := &protocol.SignalReportPayload{
+		ListenerIPv6:    ipv6Bytes,
+		ListenerPort:    uint16(l.localPort),
+		Group:           protocol.StringToGroup(l.group),
+		Interval:        uint32(l.reportInterval / time.Millisecond),
+		PacketsReceived: rc.received,
+		PacketsLost:     rc.lost,
+		Jitter:          uint32(rc.jitter),
+		BufferLevel:     uint16(queued),
+		BufferCapacity:  uint16(capacity),
+		DecodeErrors:    rc.decodeErrors,
+		Quality:         quality,
+	}
+	rc.quality = quality
+	rc.received, rc.lost, rc.decodeErrors = 0, 0, 0
+	l.receptionMu.Unlock()
+
+	packet := protocol.NewPacket(
+		protocol.PacketTypeSignalReport,
+		ipv6Bytes,
+		l.callsign,
+		protocol.MarshalSignalReport(report),
+	)
+	packet.SignalQuality = quality
+
+	return l.transport.Send(packet, l.targetIPv6, l.targetPort)
+}
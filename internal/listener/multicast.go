@@ -0,0 +1,87 @@
+package listener
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/meshradio/meshradio/pkg/network"
+	"github.com/meshradio/meshradio/pkg/protocol"
+)
+
+// joinMulticast joins the native multicast group offered in a SUBSCRIBE-ACK
+// Unicast keeps flowing until our heartbeats confirm the multicast stream
+// arrives, so a failed join or a link without multicast routing costs nothing.
+func (l *Listener) joinMulticast(ack *protocol.SubscribeAckPayload) {
+	if l.multicastCfg == nil || ack.MulticastPort == 0 || l.mcast != nil {
+		return
+	}
+
+	group := protocol.BytesToIPv6(ack.MulticastGroup)
+	t, err := network.ListenMulticast(group, int(ack.MulticastPort), l.multicastCfg.Interface, l.ssmSource)
+	if err != nil {
+		fmt.Printf("⚠️  Native multicast unavailable, staying on unicast: %v\n", err)
+		return
+	}
+	if err := t.Start(); err != nil {
+		t.Stop()
+		fmt.Printf("⚠️  Native multicast unavailable, staying on unicast: %v\n", err)
+		return
+	}
+
+	// A re-subscription can race Stop, which leaves the group
+	l.mu.Lock()
+	if !l.running {
+		l.mu.Unlock()
+		t.Stop()
+		return
+	}
+	l.mcast = t
+	l.mu.Unlock()
+	go l.multicastLoop(t)
+
+	fmt.Printf("📡 Joined native multicast [%s]:%d\n", group, ack.MulticastPort)
+}
+
+// multicastLoop receives audio from the native multicast group
+func (l *Listener) multicastLoop(t network.Transport) {
+	for {
+		packet, _, err := t.Receive()
+		if err != nil {
+			return // Transport closed
+		}
+
+		// An any-source group also carries other stations of the group:
+		// only take the stream we already receive by unicast
+		if packet.Type != protocol.PacketTypeAudio || !l.isTrackedStream(packet.StreamID) {
+			continue
+		}
+
+		select {
+		case <-l.stopChan:
+			return
+		default:
+		}
+
+		now := time.Now()
+		atomic.StoreInt64(&l.lastMulticast, now.UnixNano())
+		l.queueAudio(packet, now)
+	}
+}
+
+// isTrackedStream reports whether streamID is the stream reception tracks
+func (l *Listener) isTrackedStream(streamID uint32) bool {
+	l.receptionMu.Lock()
+	defer l.receptionMu.Unlock()
+	return l.reception.haveSeq && l.reception.streamID == streamID
+}
+
+// receivingNative reports whether multicast audio arrived recently enough for
+// the broadcaster to stop sending us a unicast copy
+func (l *Listener) receivingNative() bool {
+	last := atomic.LoadInt64(&l.lastMulticast)
+	if last == 0 {
+		return false
+	}
+	return time.Since(time.Unix(0, last)) < 2*l.heartbeatInterval
+}
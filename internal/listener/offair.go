@@ -0,0 +1,78 @@
+package listener
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/meshradio/meshradio/pkg/protocol"
+)
+
+// OffAirInfo describes why the tuned station went off air
+type OffAirInfo struct {
+	Reason uint8     // protocol.OffAir* code
+	BackAt time.Time // When the station expects to be back (zero = unknown)
+	Since  time.Time
+}
+
+// ReasonString returns a human-readable off-air reason
+func (oa OffAirInfo) ReasonString() string {
+	return protocol.OffAirReasonString(oa.Reason)
+}
+
+// handleOffAir records that the station signed off
+// Until audio resumes, the silence is not reported as packet loss.
+func (l *Listener) handleOffAir(packet *protocol.Packet) {
+	oa, err := protocol.UnmarshalOffAir(packet.Payload)
+	if err != nil {
+		fmt.Printf("Invalid OFF-AIR from %s: %v\n", packet.GetCallsign(), err)
+		return
+	}
+	if protocol.GetGroupString(oa.Group) != l.group {
+		return
+	}
+
+	info := &OffAirInfo{
+		Reason: oa.Reason,
+		BackAt: oa.BackAtTime(),
+		Since:  time.Now(),
+	}
+
+	l.mu.Lock()
+	l.offAir = info
+	l.mu.Unlock()
+	l.offAirFlag.Store(true)
+
+	msg := fmt.Sprintf("📴 Station %s is off air (%s)", packet.GetCallsign(), info.ReasonString())
+	if !info.BackAt.IsZero() {
+		msg += fmt.Sprintf(", back at %s", info.BackAt.Format("15:04"))
+	}
+	fmt.Println(msg)
+
+	if l.forward != nil {
+		l.forward(packet)
+	}
+}
+
+// backOnAir clears the off-air state when audio arrives again
+func (l *Listener) backOnAir(packet *protocol.Packet) {
+	if !l.offAirFlag.CompareAndSwap(true, false) {
+		return
+	}
+
+	l.mu.Lock()
+	l.offAir = nil
+	l.mu.Unlock()
+
+	fmt.Printf("📻 Station %s is back on air\n", packet.GetCallsign())
+}
+
+// OffAir returns why the station went off air; false while it is on air
+func (l *Listener) OffAir() (OffAirInfo, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.offAir == nil {
+		return OffAirInfo{}, false
+	}
+	return *l.offAir, true
+}
@@ -0,0 +1,360 @@
+package listener
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/meshradio/meshradio/internal/broadcaster"
+	"github.com/meshradio/meshradio/pkg/audio"
+	"github.com/meshradio/meshradio/pkg/emergency"
+	"github.com/meshradio/meshradio/pkg/network"
+	"github.com/meshradio/meshradio/pkg/protocol"
+)
+
+var (
+	simStationIP  = net.ParseIP("fd00::1")
+	simListenerIP = net.ParseIP("fd00::2")
+)
+
+// simStation is a broadcaster and a subscribed listener on a simulated network
+// The broadcaster runs in relay mode, so the test decides exactly which audio
+// packets are sent; the listener forwards what it receives instead of playing it.
+type simStation struct {
+	net         *network.SimNetwork
+	broadcaster *broadcaster.Broadcaster
+	listener    *Listener
+	received    chan *protocol.Packet
+	hold        sync.Mutex // Held by a test to stall the listener's decode worker
+}
+
+// newSimStation starts a broadcaster and subscribes a listener to it
+func newSimStation(t *testing.T, seed int64, link network.LinkConfig) *simStation {
+	t.Helper()
+	t.Setenv("MESHRADIO_LOG_DIR", t.TempDir())
+
+	sim := network.NewSimNetwork(seed)
+	sim.SetDefaultLink(link)
+
+	stationTransport, err := sim.Endpoint(simStationIP, 8790)
+	if err != nil {
+		t.Fatalf("station endpoint: %v", err)
+	}
+	listenerTransport, err := sim.Endpoint(simListenerIP, 8791)
+	if err != nil {
+		t.Fatalf("listener endpoint: %v", err)
+	}
+
+	// Beacons stay out of the way: the station only sends what the test sends
+	b, err := broadcaster.New(broadcaster.Config{
+		Callsign:          "STATION",
+		IPv6:              simStationIP,
+		Port:              8790,
+		Group:             "test",
+		AudioConfig:       audio.DefaultConfig(),
+		HeartbeatInterval: 100 * time.Millisecond,
+		LeaseTimeout:      400 * time.Millisecond,
+		BeaconInterval:    time.Hour,
+		Transport:         stationTransport,
+		Relay:             true,
+	})
+	if err != nil {
+		t.Fatalf("broadcaster.New: %v", err)
+	}
+	if err := b.Start(); err != nil {
+		t.Fatalf("broadcaster Start: %v", err)
+	}
+	t.Cleanup(func() { b.Stop() })
+
+	s := &simStation{
+		net:         sim,
+		broadcaster: b,
+		received:    make(chan *protocol.Packet, 1000),
+	}
+
+	l, err := New(Config{
+		Callsign:         "LISTENER",
+		LocalIPv6:        simListenerIP,
+		LocalPort:        8791,
+		TargetIPv6:       simStationIP,
+		TargetPort:       8790,
+		Group:            "test",
+		AudioConfig:      audio.DefaultConfig(),
+		SubscribeTimeout: 500 * time.Millisecond,
+		SubscribeRetries: 5,
+		Transport:        listenerTransport,
+		Forward: func(p *protocol.Packet) {
+			s.hold.Lock()
+			s.hold.Unlock()
+			s.received <- p
+		},
+	})
+	if err != nil {
+		t.Fatalf("listener.New: %v", err)
+	}
+	if err := l.Start(); err != nil {
+		t.Fatalf("listener Start: %v", err)
+	}
+	t.Cleanup(func() { l.Stop() })
+	s.listener = l
+
+	return s
+}
+
+// send forwards count audio packets from the station, numbered from first
+// Packets go out a millisecond apart, faster than real time but slow enough
+// that no receive queue overflows; media timestamps follow the same pace.
+func (s *simStation) send(t *testing.T, first, count int) {
+	t.Helper()
+	s.sendPriority(t, first, count, emergency.PriorityNormal)
+}
+
+// sendPriority is send with a broadcast priority
+func (s *simStation) sendPriority(t *testing.T, first, count int, priority emergency.Priority) {
+	t.Helper()
+	for i := first; i < first+count; i++ {
+		p := protocol.NewPacket(protocol.PacketTypeAudio, [16]byte{}, "ORIGIN", []byte{0x01, 0x02})
+		p.StreamID = 1
+		p.SequenceNum = uint32(i)
+		p.MediaTimestamp = uint32(i * 48)
+		p.SetPriority(uint8(priority))
+		if err := s.broadcaster.Forward(p); err != nil {
+			t.Fatalf("Forward: %v", err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// receive returns the sequence numbers of the next count forwarded packets
+func (s *simStation) receive(t *testing.T, count int) []uint32 {
+	t.Helper()
+	seqs := make([]uint32, 0, count)
+	for len(seqs) < count {
+		select {
+		case p := <-s.received:
+			seqs = append(seqs, p.SequenceNum)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("received %d of %d packets: %v", len(seqs), count, seqs)
+		}
+	}
+	return seqs
+}
+
+// flush sends a marker packet numbered next over a clean link and returns
+// everything forwarded before it. On a link without jitter nothing sent
+// earlier can arrive after the marker.
+func (s *simStation) flush(t *testing.T, next int) []uint32 {
+	t.Helper()
+	s.setLinks(network.LinkConfig{})
+	s.send(t, next, 1)
+
+	seqs := []uint32{}
+	for {
+		select {
+		case p := <-s.received:
+			if p.SequenceNum == uint32(next) {
+				return seqs
+			}
+			seqs = append(seqs, p.SequenceNum)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("marker %d never arrived, got %v", next, seqs)
+		}
+	}
+}
+
+// setLinks sets the impairments of both directions between station and listener
+func (s *simStation) setLinks(cfg network.LinkConfig) {
+	s.net.SetLink(simStationIP, simListenerIP, cfg)
+	s.net.SetLink(simListenerIP, simStationIP, cfg)
+}
+
+// waitListeners waits until the station has the given number of listeners
+func (s *simStation) waitListeners(t *testing.T, want int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for s.broadcaster.GetListenerCount() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("station has %d listeners, want %d", s.broadcaster.GetListenerCount(), want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// sequence returns first, first+1, ... count numbers
+func sequence(first, count int) []uint32 {
+	seqs := make([]uint32, count)
+	for i := range seqs {
+		seqs[i] = uint32(first + i)
+	}
+	return seqs
+}
+
+func TestSimNetworkLoss(t *testing.T) {
+	const sent = 500
+
+	// Losses on the station's link, the only random draws while it sends
+	run := func(t *testing.T, loss float64) ([]uint32, ReceptionStats) {
+		s := newSimStation(t, 1, network.LinkConfig{})
+		s.net.SetLink(simStationIP, simListenerIP, network.LinkConfig{Loss: loss})
+		s.send(t, 0, sent)
+		return s.flush(t, sent), s.listener.GetReceptionStats()
+	}
+
+	for _, loss := range []float64{0, 0.1, 0.3} {
+		t.Run(fmt.Sprintf("%.0f%% loss", loss*100), func(t *testing.T) {
+			got, stats := run(t, loss)
+			if loss == 0 && !reflect.DeepEqual(got, sequence(0, sent)) {
+				t.Fatalf("lossless link delivered %v", got)
+			}
+			if len(got) == 0 || (loss > 0 && len(got) == sent) {
+				t.Fatalf("%.0f%% loss delivered %d of %d packets", loss*100, len(got), sent)
+			}
+			for i := 1; i < len(got); i++ {
+				if got[i] <= got[i-1] {
+					t.Fatalf("packet %d arrived after %d", got[i], got[i-1])
+				}
+			}
+
+			// The same seed loses the same packets
+			if again, _ := run(t, loss); !reflect.DeepEqual(got, again) {
+				t.Errorf("same seed delivered %d packets, then %d", len(got), len(again))
+			}
+
+			// Gaps from the first packet heard up to the marker count as lost
+			wantLost := uint64(sent) - uint64(got[0]) - uint64(len(got))
+			if stats.PacketsReceived != uint64(len(got))+1 || stats.PacketsLost != wantLost {
+				t.Errorf("reception stats: %d received, %d lost; want %d, %d",
+					stats.PacketsReceived, stats.PacketsLost, len(got)+1, wantLost)
+			}
+		})
+	}
+}
+
+func TestSimNetworkDelay(t *testing.T) {
+	const delay = 150 * time.Millisecond
+
+	s := newSimStation(t, 2, network.LinkConfig{})
+	s.net.SetLink(simStationIP, simListenerIP, network.LinkConfig{Delay: delay})
+
+	start := time.Now()
+	s.send(t, 0, 1)
+	s.receive(t, 1)
+	if elapsed := time.Since(start); elapsed < delay {
+		t.Errorf("packet arrived after %v, want at least %v", elapsed, delay)
+	}
+
+	// Nothing overtakes on a link without jitter
+	s.send(t, 1, 50)
+	if got := s.receive(t, 50); !reflect.DeepEqual(got, sequence(1, 50)) {
+		t.Errorf("delayed packets arrived as %v", got)
+	}
+}
+
+func TestSimNetworkJitter(t *testing.T) {
+	const sent = 100
+
+	run := func(t *testing.T, link network.LinkConfig) ([]uint32, time.Duration) {
+		s := newSimStation(t, 4, network.LinkConfig{})
+		s.net.SetLink(simStationIP, simListenerIP, link)
+		s.send(t, 0, sent)
+		return s.receive(t, sent), s.listener.GetReceptionStats().Jitter
+	}
+
+	steady, steadyJitter := run(t, network.LinkConfig{Delay: 5 * time.Millisecond})
+	if !reflect.DeepEqual(steady, sequence(0, sent)) {
+		t.Fatalf("steady link delivered %v", steady)
+	}
+
+	got, jitter := run(t, network.LinkConfig{Delay: 5 * time.Millisecond, Jitter: 40 * time.Millisecond})
+
+	// Every packet arrives once, some overtaken by later ones
+	seen := make(map[uint32]bool)
+	inversions := 0
+	for i, seq := range got {
+		if seen[seq] || seq >= sent {
+			t.Fatalf("unexpected packet %d in %v", seq, got)
+		}
+		seen[seq] = true
+		if i > 0 && seq < got[i-1] {
+			inversions++
+		}
+	}
+	if inversions == 0 {
+		t.Error("40ms of jitter on 1ms spaced packets reordered nothing")
+	}
+
+	if jitter <= steadyJitter || jitter < 2*time.Millisecond {
+		t.Errorf("jitter estimate %v on the jittery link, %v on the steady one", jitter, steadyJitter)
+	}
+}
+
+func TestSimNetworkDuplicate(t *testing.T) {
+	const sent = 200
+
+	s := newSimStation(t, 5, network.LinkConfig{})
+	s.net.SetLink(simStationIP, simListenerIP, network.LinkConfig{Duplicate: 0.5})
+	s.send(t, 0, sent)
+
+	// The listener passes each packet on once
+	if got := s.flush(t, sent); !reflect.DeepEqual(got, sequence(0, sent)) {
+		t.Errorf("duplicating link delivered %v", got)
+	}
+	if stats := s.listener.GetReceptionStats(); stats.PacketsReceived != sent+1 {
+		t.Errorf("reception stats count %d packets, want %d", stats.PacketsReceived, sent+1)
+	}
+}
+
+func TestSimNetworkPartition(t *testing.T) {
+	link := network.LinkConfig{Delay: 5 * time.Millisecond}
+	s := newSimStation(t, 3, link)
+
+	s.send(t, 0, 10)
+	if got := s.receive(t, 10); !reflect.DeepEqual(got, sequence(0, 10)) {
+		t.Fatalf("before partition: received %v", got)
+	}
+
+	// Cut the network for longer than the lease
+	s.setLinks(network.LinkConfig{Loss: 1})
+	s.send(t, 10, 10)
+	s.waitListeners(t, 0)
+
+	// Once healed, the listener's next heartbeat is challenged and it subscribes again
+	s.setLinks(link)
+	s.waitListeners(t, 1)
+	if err := s.listener.SubscriptionEnded(); err != nil {
+		t.Fatalf("subscription ended: %v", err)
+	}
+
+	// Nothing sent during the partition turns up
+	s.send(t, 20, 10)
+	if got := s.receive(t, 10); !reflect.DeepEqual(got, sequence(20, 10)) {
+		t.Errorf("after reconnect: received %v", got)
+	}
+}
+
+func TestSimNetworkEmergencyPreemption(t *testing.T) {
+	s := newSimStation(t, 6, network.LinkConfig{})
+
+	// Stall the decode worker on packet 0 while normal audio piles up behind it
+	s.hold.Lock()
+	s.send(t, 0, 50)
+	s.sendPriority(t, 50, 5, emergency.PriorityEmergency)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for s.listener.decodeQueue.Len() < 54 {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d of 54 packets queued for decoding", s.listener.decodeQueue.Len())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	s.hold.Unlock()
+
+	// Emergency audio goes ahead of the normal backlog
+	want := append(append(sequence(0, 1), sequence(50, 5)...), sequence(1, 49)...)
+	if got := s.receive(t, 55); !reflect.DeepEqual(got, want) {
+		t.Errorf("forwarded %v, want %v", got, want)
+	}
+}
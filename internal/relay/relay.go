@@ -0,0 +1,172 @@
+package relay
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"net"
+	"sync/atomic"
+
+	"github.com/meshradio/meshradio/internal/broadcaster"
+	"github.com/meshradio/meshradio/internal/listener"
+	"github.com/meshradio/meshradio/pkg/audio"
+	"github.com/meshradio/meshradio/pkg/multicast"
+	"github.com/meshradio/meshradio/pkg/network"
+	"github.com/meshradio/meshradio/pkg/protocol"
+	"github.com/meshradio/meshradio/pkg/signing"
+)
+
+// Relay re-broadcasts an upstream station, like a repeater
+// It subscribes upstream as a listener and fans the received packets out to
+// its own subscribers without decoding them, so the origin only has to reach
+// the relay. Callsign, priority and signatures stay the origin's; every relay
+// raises the hop count, and packets past protocol.MaxHops are dropped.
+type Relay struct {
+	upstream   *listener.Listener
+	downstream *broadcaster.Broadcaster
+
+	forwarded uint64
+	hopDrops  uint64
+	errors    uint64
+}
+
+// Config holds relay configuration
+type Config struct {
+	Callsign string // Callsign of the relay itself (beacons, subscriptions)
+	IPv6     net.IP
+	Port     int    // Port downstream listeners subscribe to
+	Group    string // Group relayed (same name upstream and downstream)
+
+	UpstreamIPv6 net.IP
+	UpstreamPort int
+
+	UpstreamLocalPort int                            // Optional: local port for the upstream subscription (default: Port+1000)
+	MaxListeners      int                            // Optional: reject subscriptions beyond this count on top of the group policy (0 = unlimited)
+	SigningKey        ed25519.PrivateKey             // Optional: signs the relay's own packets (relayed audio keeps the origin's signature)
+	TrustStore        *signing.TrustStore            // Optional: listener keys; callsign rules only match SUBSCRIBEs signed by one
+	SubscriptionMgr   *multicast.SubscriptionManager // Optional: shared subscription manager. If nil, creates new one.
+	AudioConfig       *audio.StreamConfig            // Optional: stream format advertised in beacons (default: audio.DefaultConfig())
+	UpstreamTransport network.Transport              // Optional: transport for the upstream subscription
+	Transport         network.Transport              // Optional: transport for downstream listeners
+	Multicast         *network.MulticastConfig       // Optional: also send relayed audio natively to the group's IPv6 multicast address
+}
+
+// Stats counts relayed packets
+type Stats struct {
+	Forwarded uint64 // Packets sent on to downstream listeners
+	HopDrops  uint64 // Packets dropped at the hop limit (relay loop)
+	Errors    uint64 // Packets that failed to go out
+}
+
+// New creates a relay
+func New(cfg Config) (*Relay, error) {
+	if cfg.UpstreamIPv6 == nil || cfg.UpstreamPort == 0 {
+		return nil, fmt.Errorf("upstream station address is required")
+	}
+
+	upstreamLocalPort := cfg.UpstreamLocalPort
+	if upstreamLocalPort <= 0 {
+		upstreamLocalPort = cfg.Port + 1000
+	}
+
+	audioConfig := audio.DefaultConfig()
+	if cfg.AudioConfig != nil {
+		audioConfig = *cfg.AudioConfig
+	}
+
+	r := &Relay{}
+
+	downstream, err := broadcaster.New(broadcaster.Config{
+		Callsign:        cfg.Callsign,
+		IPv6:            cfg.IPv6,
+		Port:            cfg.Port,
+		Group:           cfg.Group,
+		AudioConfig:     audioConfig,
+		SubscriptionMgr: cfg.SubscriptionMgr,
+		MaxListeners:    cfg.MaxListeners,
+		SigningKey:      cfg.SigningKey,
+		TrustStore:      cfg.TrustStore,
+		Transport:       cfg.Transport,
+		Multicast:       cfg.Multicast,
+		Relay:           true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create downstream broadcaster: %w", err)
+	}
+
+	upstream, err := listener.New(listener.Config{
+		Callsign:    cfg.Callsign,
+		LocalIPv6:   cfg.IPv6,
+		LocalPort:   upstreamLocalPort,
+		TargetIPv6:  cfg.UpstreamIPv6,
+		TargetPort:  cfg.UpstreamPort,
+		Group:       cfg.Group,
+		AudioConfig: audioConfig,
+		SigningKey:  cfg.SigningKey,
+		Transport:   cfg.UpstreamTransport,
+		Forward:     r.forward,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upstream listener: %w", err)
+	}
+
+	r.upstream = upstream
+	r.downstream = downstream
+	return r, nil
+}
+
+// Start opens the relay to listeners, then subscribes upstream
+func (r *Relay) Start() error {
+	if err := r.downstream.Start(); err != nil {
+		return fmt.Errorf("failed to start downstream broadcaster: %w", err)
+	}
+	if err := r.upstream.Start(); err != nil {
+		r.downstream.Stop()
+		return fmt.Errorf("failed to subscribe upstream: %w", err)
+	}
+	return nil
+}
+
+// Stop unsubscribes upstream and stops serving listeners
+func (r *Relay) Stop() error {
+	r.upstream.Stop()
+	return r.downstream.Stop()
+}
+
+// Stats returns the relay counters
+func (r *Relay) Stats() Stats {
+	return Stats{
+		Forwarded: atomic.LoadUint64(&r.forwarded),
+		HopDrops:  atomic.LoadUint64(&r.hopDrops),
+		Errors:    atomic.LoadUint64(&r.errors),
+	}
+}
+
+// Upstream returns the listener subscribed to the upstream station
+func (r *Relay) Upstream() *listener.Listener {
+	return r.upstream
+}
+
+// Downstream returns the broadcaster serving the relay's listeners
+func (r *Relay) Downstream() *broadcaster.Broadcaster {
+	return r.downstream
+}
+
+// forward passes one upstream packet on to the downstream listeners
+func (r *Relay) forward(packet *protocol.Packet) {
+	err := r.downstream.Forward(packet)
+	switch {
+	case err == nil:
+		atomic.AddUint64(&r.forwarded, 1)
+	case errors.Is(err, broadcaster.ErrHopLimit):
+		// Log the first drop, then now and then: the loop keeps feeding us
+		if n := atomic.AddUint64(&r.hopDrops, 1); n%500 == 1 {
+			fmt.Printf("🔁 Relay loop detected: packets from %s passed %d hops, dropping\n",
+				packet.GetCallsign(), packet.HopCount)
+		}
+	default:
+		if n := atomic.AddUint64(&r.errors, 1); n%250 == 1 {
+			fmt.Printf("Relay send error: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,357 @@
+package scheduler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/meshradio/meshradio/internal/broadcaster"
+	"github.com/meshradio/meshradio/pkg/audio"
+	"github.com/meshradio/meshradio/pkg/playlist"
+	"github.com/meshradio/meshradio/pkg/protocol"
+	"github.com/meshradio/meshradio/pkg/schedule"
+)
+
+// Scheduler switches a broadcaster's source and metadata on a timetable
+// Sources are swapped into the running broadcaster with SetSource, so
+// listeners stay connected across show boundaries. An override replaces
+// the timetable until it expires or is cleared; listeners are told the
+// programme it cuts into was preempted.
+type Scheduler struct {
+	broadcaster *broadcaster.Broadcaster
+	audioConfig audio.StreamConfig
+	callsign    string
+
+	schedule      *schedule.Schedule
+	override      *schedule.Slot
+	overrideUntil time.Time // Zero = until cleared
+	mu            sync.Mutex
+
+	// Owned by the run loop
+	entered bool
+	preempt bool // Next source swapped in cuts into the programme on air
+	onAir   *schedule.Slot
+	list    *playlist.Playlist
+	next    *playlist.Track // Prepared while the current track plays
+
+	running  bool
+	wake     chan struct{}
+	stopChan chan struct{}
+	done     chan struct{}
+}
+
+// Config holds scheduler configuration
+type Config struct {
+	Broadcaster *broadcaster.Broadcaster
+	Schedule    *schedule.Schedule
+	AudioConfig audio.StreamConfig // Must match the broadcaster's stream
+	Callsign    string             // Optional: station name in metadata (default: the broadcaster's callsign)
+}
+
+// New creates a scheduler for a broadcaster
+func New(cfg Config) (*Scheduler, error) {
+	if cfg.Broadcaster == nil {
+		return nil, fmt.Errorf("scheduler needs a broadcaster")
+	}
+	if cfg.Schedule == nil {
+		return nil, fmt.Errorf("scheduler needs a schedule")
+	}
+
+	callsign := cfg.Callsign
+	if callsign == "" {
+		callsign = cfg.Broadcaster.GetCallsign()
+	}
+
+	return &Scheduler{
+		broadcaster: cfg.Broadcaster,
+		audioConfig: cfg.AudioConfig,
+		callsign:    callsign,
+		schedule:    cfg.Schedule,
+		wake:        make(chan struct{}, 1),
+		stopChan:    make(chan struct{}),
+		done:        make(chan struct{}),
+	}, nil
+}
+
+// Start puts the current slot on air and follows the timetable
+func (s *Scheduler) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running {
+		return fmt.Errorf("scheduler already running")
+	}
+	s.running = true
+
+	go s.run()
+	return nil
+}
+
+// Stop stops following the timetable; the broadcaster keeps its last source
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	if !s.running {
+		s.mu.Unlock()
+		return
+	}
+	s.running = false
+	close(s.stopChan)
+	s.mu.Unlock()
+
+	<-s.done
+}
+
+// Override puts a slot on air instead of the timetable until the given time
+// (zero = until ClearOverride). Days, Start and End of the slot are ignored.
+func (s *Scheduler) Override(slot schedule.Slot, until time.Time) {
+	s.mu.Lock()
+	s.override = &slot
+	s.overrideUntil = until
+	s.mu.Unlock()
+
+	s.poke()
+}
+
+// ClearOverride returns to the timetable
+func (s *Scheduler) ClearOverride() {
+	s.mu.Lock()
+	s.override = nil
+	s.overrideUntil = time.Time{}
+	s.mu.Unlock()
+
+	s.poke()
+}
+
+// SetSchedule replaces the timetable (e.g. after editing the schedule file)
+// A programme that is still on air in the new timetable plays on uninterrupted.
+func (s *Scheduler) SetSchedule(sched *schedule.Schedule) {
+	s.mu.Lock()
+	s.schedule = sched
+	s.mu.Unlock()
+
+	s.poke()
+}
+
+// Current returns the slot on air (false if nothing is scheduled)
+func (s *Scheduler) Current() (schedule.Slot, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	slot := s.slotAt(time.Now())
+	if slot == nil {
+		return schedule.Slot{}, false
+	}
+	return *slot, true
+}
+
+// poke makes the run loop re-evaluate the timetable
+func (s *Scheduler) poke() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run follows the timetable until Stop
+func (s *Scheduler) run() {
+	defer close(s.done)
+
+	for {
+		now := time.Now()
+
+		s.mu.Lock()
+		slot := s.slotAt(now)
+		overriding := slot != nil && slot == s.override
+		changeAt := s.schedule.NextChange(now)
+		if s.override != nil && !s.overrideUntil.IsZero() {
+			changeAt = s.overrideUntil
+		} else if s.override != nil {
+			changeAt = time.Time{}
+		}
+		s.mu.Unlock()
+
+		if !s.entered || !sameProgramme(slot, s.onAir) {
+			s.preempt = s.entered && overriding
+			s.enter(slot)
+			s.preempt = false
+		}
+
+		var timer *time.Timer
+		var timeout <-chan time.Time
+		if !changeAt.IsZero() {
+			timer = time.NewTimer(time.Until(changeAt))
+			timeout = timer.C
+		}
+
+		select {
+		case <-timeout:
+		case <-s.wake:
+		case <-s.broadcaster.SourceEnded():
+			s.sourceEnded()
+		case <-s.stopChan:
+			if timer != nil {
+				timer.Stop()
+			}
+			s.dropNext()
+			return
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// slotAt returns the slot on air at t, override first. Caller must hold s.mu.
+func (s *Scheduler) slotAt(t time.Time) *schedule.Slot {
+	if s.override != nil {
+		if s.overrideUntil.IsZero() || t.Before(s.overrideUntil) {
+			return s.override
+		}
+		s.override = nil
+		s.overrideUntil = time.Time{}
+	}
+	return s.schedule.At(t)
+}
+
+// enter puts a slot on air (nil = nothing scheduled, silence)
+func (s *Scheduler) enter(slot *schedule.Slot) {
+	s.dropNext()
+	s.list = nil
+	s.onAir = slot
+	s.entered = true
+
+	if slot == nil {
+		fmt.Println("📅 Nothing scheduled, staying on air with silence")
+		s.play(audio.NewSilenceSource(s.audioConfig), s.slotMetadata(nil))
+		return
+	}
+
+	fmt.Printf("📅 On air: %s\n", slot)
+
+	switch slot.Kind {
+	case schedule.KindPlaylist:
+		list, err := playlist.Scan(slot.Source)
+		if err != nil || list.Len() == 0 {
+			fmt.Printf("⚠️  No music in %s (%v), playing silence\n", slot.Source, err)
+			s.play(audio.NewSilenceSource(s.audioConfig), s.slotMetadata(slot))
+			return
+		}
+		s.list = list
+		s.nextTrack()
+
+	case schedule.KindLive:
+		s.play(audio.NewMicrophoneSource(s.audioConfig), s.slotMetadata(slot))
+
+	case schedule.KindID:
+		src, err := audio.NewFFmpegSource(slot.Source, s.audioConfig)
+		if err == nil {
+			err = src.Start()
+		}
+		if err != nil {
+			fmt.Printf("⚠️  Station ID %s failed: %v\n", slot.Source, err)
+			s.play(audio.NewSilenceSource(s.audioConfig), s.slotMetadata(slot))
+			return
+		}
+		s.play(src, s.slotMetadata(slot))
+
+	default:
+		s.play(audio.NewSilenceSource(s.audioConfig), s.slotMetadata(slot))
+	}
+}
+
+// sourceEnded moves on when the current source runs out
+func (s *Scheduler) sourceEnded() {
+	if s.list != nil {
+		fmt.Printf("   ✅ Completed\n\n")
+		s.nextTrack()
+		return
+	}
+
+	// Station IDs (or a failed input) leave the rest of the slot silent
+	s.play(audio.NewSilenceSource(s.audioConfig), s.slotMetadata(s.onAir))
+}
+
+// nextTrack plays the prepared track (or opens one) and prepares the next
+func (s *Scheduler) nextTrack() {
+	stationText := s.stationText(s.onAir)
+
+	current := s.next
+	s.next = nil
+	if current == nil {
+		track, err := s.list.OpenNext(s.audioConfig, stationText, true)
+		if err != nil {
+			fmt.Printf("   ❌ Error: %v\n", err)
+			s.list = nil
+			s.play(audio.NewSilenceSource(s.audioConfig), s.slotMetadata(s.onAir))
+			return
+		}
+		current = track
+	}
+
+	current.Announce(s.list.Len())
+	s.play(current.Source, current.Metadata)
+
+	// Prepare the next track while this one plays
+	next, err := s.list.OpenNext(s.audioConfig, stationText, true)
+	if err != nil {
+		fmt.Printf("   ❌ Error: %v\n", err)
+		return
+	}
+	s.next = next
+}
+
+// play swaps a source into the broadcaster and announces it
+func (s *Scheduler) play(src audio.AudioSource, md protocol.Metadata) {
+	// Forget an end signal of the source being replaced
+	select {
+	case <-s.broadcaster.SourceEnded():
+	default:
+	}
+
+	swap := s.broadcaster.SetSource
+	if s.preempt {
+		swap = s.broadcaster.Preempt
+		s.preempt = false
+	}
+	if err := swap(src); err != nil {
+		fmt.Printf("⚠️  Failed to switch source: %v\n", err)
+		src.Stop()
+		return
+	}
+	s.broadcaster.SetMetadata(md)
+}
+
+// dropNext stops a track prepared for a programme that is going off air
+func (s *Scheduler) dropNext() {
+	if s.next != nil {
+		s.next.Source.Stop()
+		s.next = nil
+	}
+}
+
+// slotMetadata is the now-playing info for slots without tracks
+func (s *Scheduler) slotMetadata(slot *schedule.Slot) protocol.Metadata {
+	md := protocol.Metadata{StationText: s.callsign}
+	if slot != nil {
+		md.Title = slot.Title
+	}
+	return md
+}
+
+// stationText names the programme in track metadata
+func (s *Scheduler) stationText(slot *schedule.Slot) string {
+	if slot != nil && slot.Title != "" {
+		return fmt.Sprintf("%s: %s", s.callsign, slot.Title)
+	}
+	return s.callsign
+}
+
+// sameProgramme reports whether two slots play the same thing, so a
+// programme spanning adjacent slots (or a reload) plays on uninterrupted
+func sameProgramme(a, b *schedule.Slot) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Kind == b.Kind && a.Source == b.Source && a.Title == b.Title
+}
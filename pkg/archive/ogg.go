@@ -0,0 +1,180 @@
+package archive
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Ogg page header flags
+const (
+	oggBOS = 0x02 // First page of a logical stream
+	oggEOS = 0x04 // Last page of a logical stream
+)
+
+// maxPageSegments is the lacing table limit of one Ogg page
+const maxPageSegments = 255
+
+// opusGranuleRate is the clock of Ogg Opus granule positions, whatever the input rate
+const opusGranuleRate = 48000
+
+// oggCRCTable is the CRC-32 of the Ogg spec (polynomial 0x04c11db7, not reflected)
+var oggCRCTable = func() [256]uint32 {
+	var table [256]uint32
+	for i := range table {
+		r := uint32(i) << 24
+		for j := 0; j < 8; j++ {
+			if r&0x80000000 != 0 {
+				r = r<<1 ^ 0x04c11db7
+			} else {
+				r <<= 1
+			}
+		}
+		table[i] = r
+	}
+	return table
+}()
+
+func oggCRC(crc uint32, data []byte) uint32 {
+	for _, b := range data {
+		crc = crc<<8 ^ oggCRCTable[byte(crc>>24)^b]
+	}
+	return crc
+}
+
+// oggStream writes one logical Ogg Opus stream (a "link" of a chained file)
+// Packets are collected into pages and written whenever a page fills up or
+// Flush is called, so a crash loses at most the unflushed page.
+type oggStream struct {
+	w        io.Writer
+	serial   uint32
+	pageSeq  uint32
+	granule  uint64 // Granule position after the last packet added
+	begun    bool   // BOS page written
+	segments []byte
+	body     []byte
+}
+
+// writeHeaders starts the stream with the OpusHead and OpusTags pages (RFC 7845)
+func (s *oggStream) writeHeaders(channels, inputRate int, preSkip uint16, vendor string, tags map[string]string) error {
+	head := make([]byte, 19)
+	copy(head, "OpusHead")
+	head[8] = 1 // Version
+	head[9] = byte(channels)
+	binary.LittleEndian.PutUint16(head[10:12], preSkip)
+	binary.LittleEndian.PutUint32(head[12:16], uint32(inputRate))
+	// Output gain 0, channel mapping family 0 (mono/stereo)
+
+	if err := s.writePacketPage(head, oggBOS, 0); err != nil {
+		return err
+	}
+	return s.writePacketPage(opusTags(vendor, tags), 0, 0)
+}
+
+// opusTags builds an OpusTags packet with Vorbis comments (sorted for stable output)
+func opusTags(vendor string, tags map[string]string) []byte {
+	keys := make([]string, 0, len(tags))
+	for k, v := range tags {
+		if v != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	buf := []byte("OpusTags")
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(vendor)))
+	buf = append(buf, vendor...)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(keys)))
+	for _, k := range keys {
+		comment := k + "=" + tags[k]
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(comment)))
+		buf = append(buf, comment...)
+	}
+	return buf
+}
+
+// writePacketPage writes a header packet on a page of its own (as RFC 7845 requires)
+func (s *oggStream) writePacketPage(packet []byte, flags byte, granule uint64) error {
+	if len(packet) >= maxPageSegments*255 {
+		return fmt.Errorf("ogg header packet too large (%d bytes)", len(packet))
+	}
+	s.segments = lacing(s.segments[:0], len(packet))
+	s.body = append(s.body[:0], packet...)
+	return s.writePage(flags, granule)
+}
+
+// addPacket queues an audio packet covering the given number of 48 kHz samples
+func (s *oggStream) addPacket(packet []byte, samples uint64) error {
+	if len(packet) >= maxPageSegments*255 {
+		return fmt.Errorf("ogg packet too large (%d bytes)", len(packet))
+	}
+
+	// Packets never span pages: flush first if this one doesn't fit
+	if len(s.segments)+len(packet)/255+1 > maxPageSegments {
+		if err := s.Flush(); err != nil {
+			return err
+		}
+	}
+
+	s.segments = lacing(s.segments, len(packet))
+	s.body = append(s.body, packet...)
+	s.granule += samples
+	return nil
+}
+
+// Flush writes the queued packets as one page
+func (s *oggStream) Flush() error {
+	if len(s.segments) == 0 {
+		return nil
+	}
+	return s.writePage(0, s.granule)
+}
+
+// Close flushes the queued packets and marks the end of the stream
+func (s *oggStream) Close() error {
+	if !s.begun {
+		return nil
+	}
+	// Queued packets go on the EOS page; an empty EOS page is valid too
+	return s.writePage(oggEOS, s.granule)
+}
+
+// writePage writes the queued segments as a page and resets the queue
+func (s *oggStream) writePage(flags byte, granule uint64) error {
+	header := make([]byte, 27, 27+len(s.segments))
+	copy(header, "OggS")
+	header[4] = 0 // Version
+	header[5] = flags
+	binary.LittleEndian.PutUint64(header[6:14], granule)
+	binary.LittleEndian.PutUint32(header[14:18], s.serial)
+	binary.LittleEndian.PutUint32(header[18:22], s.pageSeq)
+	header[26] = byte(len(s.segments))
+	header = append(header, s.segments...)
+
+	crc := oggCRC(0, header)
+	crc = oggCRC(crc, s.body)
+	binary.LittleEndian.PutUint32(header[22:26], crc)
+
+	s.pageSeq++
+	s.begun = true
+	s.segments = s.segments[:0]
+
+	if _, err := s.w.Write(header); err != nil {
+		return fmt.Errorf("failed to write ogg page: %w", err)
+	}
+	if _, err := s.w.Write(s.body); err != nil {
+		return fmt.Errorf("failed to write ogg page: %w", err)
+	}
+	s.body = s.body[:0]
+	return nil
+}
+
+// lacing appends the Ogg lacing values of a packet to a segment table
+func lacing(segments []byte, size int) []byte {
+	for size >= 255 {
+		segments = append(segments, 255)
+		size -= 255
+	}
+	return append(segments, byte(size))
+}
@@ -0,0 +1,357 @@
+package archive
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/meshradio/meshradio/pkg/protocol"
+)
+
+// Archive defaults
+const (
+	DefaultRollInterval = time.Hour
+	archiveExt          = ".opus"
+	archiveVendor       = "meshradio"
+
+	// encoderPreSkip is the Opus encoder lookahead at 48 kHz, trimmed from
+	// the first link of a broadcast. Later links continue a running encoder
+	// and start without pre-skip.
+	encoderPreSkip = 312
+)
+
+// Config holds the archive policy
+type Config struct {
+	Dir          string        // Directory for archive files (created if missing)
+	RollInterval time.Duration // Optional: start a new file on each multiple of this wall-clock interval (default: 1h)
+	MaxFileSize  int64         // Optional: also start a new file once a file reaches this many bytes (0 = no limit)
+	Retention    time.Duration // Optional: delete archive files older than this (0 = keep forever)
+	MaxFiles     int           // Optional: keep at most this many archive files (0 = unlimited)
+}
+
+// Stream describes the Opus stream being archived
+type Stream struct {
+	Callsign   string
+	Group      string
+	SampleRate int // Encoder input rate
+	Channels   int // 1 or 2
+	FrameSize  int // Samples per channel per frame, at SampleRate
+}
+
+// Recorder tees encoded Opus frames into rolling Ogg Opus files
+// Frames are stored exactly as encoded, never re-encoded. Each change of
+// now-playing metadata starts a new link of a chained Ogg stream whose
+// OpusTags carry that metadata and the wall-clock time it went on air, so
+// players show it and an audit can tell what went out when.
+type Recorder struct {
+	config          Config
+	stream          Stream
+	prefix          string
+	samplesPerFrame uint64 // 48 kHz granule samples per frame
+	framesPerPage   int
+
+	file       *os.File
+	fileName   string
+	fileSize   int64
+	rollAt     time.Time
+	ogg        *oggStream
+	pageFrames int
+	started    bool // A frame was written since New (encoder lookahead trimmed)
+
+	metadata    *protocol.Metadata
+	metadataNew bool // Changed since the current link started
+
+	closed   bool
+	cleaning atomic.Bool
+	mu       sync.Mutex
+}
+
+// New creates a recorder; files are opened when the first frame arrives
+func New(cfg Config, stream Stream) (*Recorder, error) {
+	if cfg.Dir == "" {
+		return nil, fmt.Errorf("archive directory not set")
+	}
+	if stream.SampleRate <= 0 || stream.FrameSize <= 0 {
+		return nil, fmt.Errorf("invalid archive stream: %d Hz, %d samples per frame", stream.SampleRate, stream.FrameSize)
+	}
+	if stream.Channels != 1 && stream.Channels != 2 {
+		return nil, fmt.Errorf("archive supports mono and stereo only (got %d channels)", stream.Channels)
+	}
+	if cfg.RollInterval <= 0 {
+		cfg.RollInterval = DefaultRollInterval
+	}
+	if err := os.MkdirAll(cfg.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	// Flush a page about once a second
+	framesPerPage := stream.SampleRate / stream.FrameSize
+	if framesPerPage < 1 {
+		framesPerPage = 1
+	}
+
+	return &Recorder{
+		config:          cfg,
+		stream:          stream,
+		prefix:          fileSafe(stream.Callsign) + "-" + fileSafe(stream.Group) + "-",
+		samplesPerFrame: uint64(stream.FrameSize) * opusGranuleRate / uint64(stream.SampleRate),
+		framesPerPage:   framesPerPage,
+	}, nil
+}
+
+// WriteFrame appends one encoded Opus frame
+// On a write error the file is closed and the next frame starts a new one.
+func (r *Recorder) WriteFrame(frame []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return nil
+	}
+
+	now := time.Now()
+	if r.file != nil && (!now.Before(r.rollAt) || (r.config.MaxFileSize > 0 && r.fileSize >= r.config.MaxFileSize)) {
+		r.closeFile()
+	}
+
+	var err error
+	if r.file == nil {
+		err = r.openFile(now)
+	} else if r.metadataNew {
+		// New metadata: end this link and chain one with the new tags
+		if err = r.ogg.Close(); err == nil {
+			err = r.startLink(now)
+		}
+	}
+	if err == nil {
+		err = r.ogg.addPacket(frame, r.samplesPerFrame)
+	}
+	if err == nil {
+		r.started = true
+		r.pageFrames++
+		if r.pageFrames >= r.framesPerPage {
+			r.pageFrames = 0
+			err = r.ogg.Flush()
+		}
+	}
+
+	if err != nil {
+		r.abandonFile()
+		return fmt.Errorf("archive write failed: %w", err)
+	}
+	return nil
+}
+
+// SetMetadata records the now-playing metadata; a change starts a new link
+func (r *Recorder) SetMetadata(md protocol.Metadata) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.metadata != nil && sameMetadata(*r.metadata, md) {
+		return
+	}
+	r.metadata = &md
+	r.metadataNew = r.file != nil
+}
+
+// CurrentFile returns the path of the file being written ("" if none)
+func (r *Recorder) CurrentFile() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return ""
+	}
+	return filepath.Join(r.config.Dir, r.fileName)
+}
+
+// Close finishes the current file; later frames are ignored
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.closed = true
+	return r.closeFile()
+}
+
+// openFile creates a new archive file and starts its first link
+func (r *Recorder) openFile(now time.Time) error {
+	base := r.prefix + now.Format("20060102-150405")
+
+	// A size roll within the same second gets a suffix
+	var f *os.File
+	var name string
+	for n := 1; ; n++ {
+		name = base + archiveExt
+		if n > 1 {
+			name = fmt.Sprintf("%s-%d%s", base, n, archiveExt)
+		}
+		var err error
+		f, err = os.OpenFile(filepath.Join(r.config.Dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			break
+		}
+		if !os.IsExist(err) || n >= 100 {
+			return fmt.Errorf("failed to create archive file: %w", err)
+		}
+	}
+
+	r.file = f
+	r.fileName = name
+	r.fileSize = 0
+	r.rollAt = now.Truncate(r.config.RollInterval).Add(r.config.RollInterval)
+	fmt.Printf("💾 Archiving to %s\n", filepath.Join(r.config.Dir, name))
+
+	if err := r.startLink(now); err != nil {
+		return err
+	}
+
+	// Apply the retention policy in the background
+	if (r.config.Retention > 0 || r.config.MaxFiles > 0) && r.cleaning.CompareAndSwap(false, true) {
+		go func(current string) {
+			defer r.cleaning.Store(false)
+			r.cleanup(current)
+		}(name)
+	}
+	return nil
+}
+
+// startLink begins a logical stream tagged with the current metadata
+func (r *Recorder) startLink(now time.Time) error {
+	r.ogg = &oggStream{
+		w:      countingWriter{w: r.file, n: &r.fileSize},
+		serial: rand.Uint32(),
+	}
+	r.pageFrames = 0
+	r.metadataNew = false
+
+	var preSkip uint16
+	if !r.started {
+		preSkip = encoderPreSkip
+	}
+
+	tags := map[string]string{
+		"STATION": r.stream.Callsign,
+		"GROUP":   r.stream.Group,
+		"DATE":    now.Format(time.RFC3339),
+	}
+	if md := r.metadata; md != nil {
+		tags["TITLE"] = md.Title
+		tags["ARTIST"] = md.Artist
+		tags["ALBUM"] = md.Album
+		tags["DESCRIPTION"] = md.StationText
+	}
+
+	return r.ogg.writeHeaders(r.stream.Channels, r.stream.SampleRate, preSkip, archiveVendor, tags)
+}
+
+// closeFile ends the current link and closes the file
+func (r *Recorder) closeFile() error {
+	if r.file == nil {
+		return nil
+	}
+
+	err := r.ogg.Close()
+	if cerr := r.file.Close(); err == nil && cerr != nil {
+		err = fmt.Errorf("failed to close archive file: %w", cerr)
+	}
+	r.file = nil
+	r.ogg = nil
+	return err
+}
+
+// abandonFile closes a file after a write error without writing more to it
+func (r *Recorder) abandonFile() {
+	if r.file != nil {
+		r.file.Close()
+	}
+	r.file = nil
+	r.ogg = nil
+}
+
+// cleanup deletes archive files of this stream past the retention policy
+func (r *Recorder) cleanup(current string) {
+	entries, err := os.ReadDir(r.config.Dir)
+	if err != nil {
+		fmt.Printf("⚠️  Archive cleanup failed: %v\n", err)
+		return
+	}
+
+	// File names sort by start time (without the extension, so "-2" sorts last)
+	var files []os.DirEntry
+	for _, e := range entries {
+		name := e.Name()
+		if !e.IsDir() && name != current && strings.HasPrefix(name, r.prefix) && strings.HasSuffix(name, archiveExt) {
+			files = append(files, e)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return strings.TrimSuffix(files[i].Name(), archiveExt) < strings.TrimSuffix(files[j].Name(), archiveExt)
+	})
+
+	// The current file counts towards MaxFiles
+	excess := 0
+	if r.config.MaxFiles > 0 {
+		excess = len(files) + 1 - r.config.MaxFiles
+	}
+	cutoff := time.Now().Add(-r.config.Retention)
+
+	for i, e := range files {
+		expired := false
+		if r.config.Retention > 0 {
+			if info, err := e.Info(); err == nil && info.ModTime().Before(cutoff) {
+				expired = true
+			}
+		}
+		if i >= excess && !expired {
+			continue
+		}
+
+		path := filepath.Join(r.config.Dir, e.Name())
+		if err := os.Remove(path); err != nil {
+			fmt.Printf("⚠️  Failed to remove old archive %s: %v\n", path, err)
+			continue
+		}
+		fmt.Printf("🗑️  Removed old archive %s\n", path)
+	}
+}
+
+// sameMetadata compares metadata ignoring the playback position
+func sameMetadata(a, b protocol.Metadata) bool {
+	return a.Title == b.Title && a.Artist == b.Artist && a.Album == b.Album &&
+		a.Duration == b.Duration && a.StationText == b.StationText
+}
+
+// fileSafe makes a callsign or group usable in a file name
+func fileSafe(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		}
+		return '_'
+	}, s)
+	if s == "" {
+		return "_"
+	}
+	return s
+}
+
+// countingWriter tracks the size of the file being written
+type countingWriter struct {
+	w io.Writer
+	n *int64
+}
+
+func (c countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	*c.n += int64(n)
+	return n, err
+}
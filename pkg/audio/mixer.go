@@ -0,0 +1,583 @@
+package audio
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"time"
+)
+
+// Ducking defaults
+const (
+	DefaultDuckDepth     = 12.0  // dB the bed drops while a voice talks
+	DefaultDuckThreshold = -40.0 // dBFS a voice input must exceed
+	DefaultDuckAttack    = 20 * time.Millisecond
+	DefaultDuckRelease   = 400 * time.Millisecond
+	DefaultDuckHold      = 500 * time.Millisecond
+)
+
+// mixerBufferFrames caps how far an input may run ahead of the mixer clock
+const mixerBufferFrames = 4
+
+// MixerConfig holds mixer configuration
+type MixerConfig struct {
+	Stream  StreamConfig   // Output format, also the frame clock
+	Ducking *DuckingConfig // Optional: lower bed inputs while a voice input talks (nil = off)
+}
+
+// DuckingConfig controls automatic ducking
+type DuckingConfig struct {
+	Depth     float64       // Optional: dB the bed inputs drop (default: 12)
+	Threshold float64       // Optional: voice level in dBFS that starts ducking (default: -40)
+	Attack    time.Duration // Optional: time to duck down (default: 20ms)
+	Release   time.Duration // Optional: time to come back up (default: 400ms)
+	Hold      time.Duration // Optional: stay ducked after the voice stops (default: 500ms)
+}
+
+// MixerInput describes one input of a mixer
+type MixerInput struct {
+	Name   string
+	Source AudioSource
+	Gain   float64 // Optional: gain in dB (default: 0)
+	Pan    float64 // Optional: -1 (left) to 1 (right), balance for stereo inputs (default: 0)
+	Muted  bool    // Optional: start muted
+	Voice  bool    // Optional: talking on this input ducks the bed inputs
+	Bed    bool    // Optional: lowered while a voice input talks
+}
+
+// Mixer is an AudioSource that mixes several sources into one stream
+// Each input is read on its own goroutine and buffered, so a slow or ended
+// input plays as silence instead of stalling the others. Read is paced by
+// the frame clock of the output format, like a live source; an input that
+// runs ahead (a file decodes as fast as it is read) waits for buffer space.
+type Mixer struct {
+	config  StreamConfig
+	ducking *DuckingConfig
+
+	inputs []*mixerInput
+	mu     sync.Mutex
+
+	running  bool
+	stopChan chan struct{}
+	next     time.Time // When the next frame is due
+
+	duckGain  float64 // Current bed gain, linear
+	lastVoice time.Time
+
+	ended chan string
+}
+
+// mixerInput is an input with its buffered samples
+type mixerInput struct {
+	MixerInput
+	gain      float64 // Linear
+	buf       []int16 // Samples in the output rate, source channel count
+	channels  int
+	resampler *SimpleResampler
+	stop      chan struct{}
+	space     chan struct{} // Signalled when the mixer takes a frame
+	bufMu     sync.Mutex
+}
+
+// NewMixer creates a mixer without inputs
+func NewMixer(cfg MixerConfig) *Mixer {
+	var ducking *DuckingConfig
+	if cfg.Ducking != nil {
+		d := *cfg.Ducking
+		if d.Depth <= 0 {
+			d.Depth = DefaultDuckDepth
+		}
+		if d.Threshold == 0 {
+			d.Threshold = DefaultDuckThreshold
+		}
+		if d.Attack <= 0 {
+			d.Attack = DefaultDuckAttack
+		}
+		if d.Release <= 0 {
+			d.Release = DefaultDuckRelease
+		}
+		if d.Hold <= 0 {
+			d.Hold = DefaultDuckHold
+		}
+		ducking = &d
+	}
+
+	return &Mixer{
+		config:   cfg.Stream,
+		ducking:  ducking,
+		duckGain: 1,
+		ended:    make(chan string, 8),
+	}
+}
+
+// AddInput adds an input; it starts playing at once if the mixer runs
+func (m *Mixer) AddInput(in MixerInput) error {
+	if in.Source == nil {
+		return fmt.Errorf("input '%s' has no source", in.Name)
+	}
+	if in.Source.Channels() != 1 && in.Source.Channels() != 2 {
+		return fmt.Errorf("input '%s' has %d channels, only mono and stereo are supported", in.Name, in.Source.Channels())
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.find(in.Name) != nil {
+		return fmt.Errorf("input '%s' already exists", in.Name)
+	}
+
+	input := m.newInput(in)
+	if m.running {
+		if err := m.startInput(input); err != nil {
+			return err
+		}
+	}
+	m.inputs = append(m.inputs, input)
+	return nil
+}
+
+// RemoveInput stops an input and takes it out of the mix
+func (m *Mixer) RemoveInput(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, input := range m.inputs {
+		if input.Name == name {
+			m.stopInput(input)
+			m.inputs = append(m.inputs[:i], m.inputs[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("no input '%s'", name)
+}
+
+// ReplaceSource swaps the source of an input, keeping its gain, pan and role
+// The old source is stopped. Use it to move a bed input to the next track.
+func (m *Mixer) ReplaceSource(name string, src AudioSource) error {
+	if src == nil {
+		return fmt.Errorf("input '%s': source is nil", name)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	old := m.find(name)
+	if old == nil {
+		return fmt.Errorf("no input '%s'", name)
+	}
+
+	settings := old.MixerInput
+	settings.Source = src
+	input := m.newInput(settings)
+	input.gain = old.gain
+
+	if m.running {
+		if err := m.startInput(input); err != nil {
+			return err
+		}
+	}
+	m.stopInput(old)
+
+	for i := range m.inputs {
+		if m.inputs[i] == old {
+			m.inputs[i] = input
+		}
+	}
+	return nil
+}
+
+// SetGain sets the gain of an input in dB
+func (m *Mixer) SetGain(name string, db float64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	input := m.find(name)
+	if input == nil {
+		return fmt.Errorf("no input '%s'", name)
+	}
+	input.Gain = db
+	input.gain = dbToGain(db)
+	return nil
+}
+
+// SetMute mutes or unmutes an input
+func (m *Mixer) SetMute(name string, muted bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	input := m.find(name)
+	if input == nil {
+		return fmt.Errorf("no input '%s'", name)
+	}
+	input.Muted = muted
+	return nil
+}
+
+// SetPan sets the pan of an input (-1 left to 1 right)
+func (m *Mixer) SetPan(name string, pan float64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	input := m.find(name)
+	if input == nil {
+		return fmt.Errorf("no input '%s'", name)
+	}
+	input.Pan = clampPan(pan)
+	return nil
+}
+
+// Ducked reports whether the bed inputs are currently lowered
+func (m *Mixer) Ducked() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.duckGain < 0.99
+}
+
+// InputEnded receives the name of each input whose source ran out (io.EOF).
+// The input stays in the mix as silence until replaced or removed.
+func (m *Mixer) InputEnded() <-chan string {
+	return m.ended
+}
+
+// Start starts all inputs and the frame clock
+func (m *Mixer) Start() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.running {
+		return fmt.Errorf("mixer already running")
+	}
+
+	m.stopChan = make(chan struct{})
+	for _, input := range m.inputs {
+		if err := m.startInput(input); err != nil {
+			for _, started := range m.inputs {
+				if started == input {
+					break
+				}
+				m.stopInput(started)
+			}
+			return err
+		}
+	}
+
+	m.running = true
+	m.next = time.Now()
+	return nil
+}
+
+// Stop stops the mixer and all inputs
+func (m *Mixer) Stop() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.running {
+		return nil
+	}
+
+	m.running = false
+	close(m.stopChan)
+	for _, input := range m.inputs {
+		m.stopInput(input)
+	}
+	return nil
+}
+
+// Read returns the next mixed frame, waiting for the frame clock
+func (m *Mixer) Read() ([]int16, error) {
+	m.mu.Lock()
+	if !m.running {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("mixer not running")
+	}
+	stopChan := m.stopChan
+	wait := time.Until(m.next)
+	m.next = m.next.Add(m.frameDuration())
+	if wait < -10*m.frameDuration() {
+		// Reader fell far behind: resync instead of bursting to catch up
+		m.next = time.Now().Add(m.frameDuration())
+	}
+	m.mu.Unlock()
+
+	if wait > 0 {
+		select {
+		case <-time.After(wait):
+		case <-stopChan:
+			return nil, fmt.Errorf("mixer not running")
+		}
+	}
+
+	return m.mix(), nil
+}
+
+// SampleRate returns the output sample rate
+func (m *Mixer) SampleRate() int {
+	return m.config.SampleRate
+}
+
+// Channels returns the output channel count
+func (m *Mixer) Channels() int {
+	return m.config.Channels
+}
+
+// IsRunning returns whether the mixer is running
+func (m *Mixer) IsRunning() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.running
+}
+
+// mix builds one output frame from the buffered input samples
+func (m *Mixer) mix() []int16 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	frameSize := m.config.FrameSize
+	outChannels := m.config.Channels
+	acc := make([]float64, frameSize*outChannels)
+
+	// Pull every input's frame first: voice levels decide the bed gain
+	frames := make([][]int16, len(m.inputs))
+	voiceActive := false
+	for i, input := range m.inputs {
+		frames[i] = input.take(frameSize)
+		if input.Voice && !input.Muted && m.ducking != nil &&
+			levelDB(frames[i], input.gain) > m.ducking.Threshold {
+			voiceActive = true
+		}
+	}
+
+	// Ramp the bed gain across the frame so ducking doesn't click
+	startDuck := m.duckGain
+	endDuck := m.nextDuckGain(voiceActive)
+	m.duckGain = endDuck
+
+	for i, input := range m.inputs {
+		if input.Muted {
+			continue
+		}
+		frame := frames[i]
+		left, right := panGains(input.Pan, input.channels)
+
+		for s := 0; s < frameSize; s++ {
+			gain := input.gain
+			if input.Bed {
+				gain *= startDuck + (endDuck-startDuck)*float64(s)/float64(frameSize)
+			}
+
+			// Mono output: pan has nothing to place, stereo inputs are folded down
+			if outChannels == 1 {
+				if input.channels == 2 {
+					acc[s] += (float64(frame[s*2]) + float64(frame[s*2+1])) / 2 * gain
+				} else {
+					acc[s] += float64(frame[s]) * gain
+				}
+				continue
+			}
+
+			if input.channels == 2 {
+				acc[s*2] += float64(frame[s*2]) * left * gain
+				acc[s*2+1] += float64(frame[s*2+1]) * right * gain
+			} else {
+				acc[s*2] += float64(frame[s]) * left * gain
+				acc[s*2+1] += float64(frame[s]) * right * gain
+			}
+		}
+	}
+
+	out := make([]int16, len(acc))
+	for i, v := range acc {
+		out[i] = clampSample(v)
+	}
+	return out
+}
+
+// nextDuckGain moves the bed gain one frame towards its target
+// Caller must hold m.mu.
+func (m *Mixer) nextDuckGain(voiceActive bool) float64 {
+	if m.ducking == nil {
+		return 1
+	}
+
+	now := time.Now()
+	if voiceActive {
+		m.lastVoice = now
+	}
+
+	target, timeConstant := 1.0, m.ducking.Release
+	if now.Sub(m.lastVoice) < m.ducking.Hold {
+		target, timeConstant = dbToGain(-m.ducking.Depth), m.ducking.Attack
+	}
+
+	coef := 1 - math.Exp(-float64(m.frameDuration())/float64(timeConstant))
+	return m.duckGain + (target-m.duckGain)*coef
+}
+
+// frameDuration returns the length of one output frame
+func (m *Mixer) frameDuration() time.Duration {
+	return time.Duration(m.config.FrameSize) * time.Second / time.Duration(m.config.SampleRate)
+}
+
+// find returns the input with a name. Caller must hold m.mu.
+func (m *Mixer) find(name string) *mixerInput {
+	for _, input := range m.inputs {
+		if input.Name == name {
+			return input
+		}
+	}
+	return nil
+}
+
+// newInput prepares an input for the output format
+func (m *Mixer) newInput(in MixerInput) *mixerInput {
+	in.Pan = clampPan(in.Pan)
+	return &mixerInput{
+		MixerInput: in,
+		gain:       dbToGain(in.Gain),
+		channels:   in.Source.Channels(),
+		resampler:  NewSimpleResampler(in.Source.SampleRate(), m.config.SampleRate, in.Source.Channels()),
+		space:      make(chan struct{}, 1),
+	}
+}
+
+// startInput starts an input's source and reader. Caller must hold m.mu.
+func (m *Mixer) startInput(input *mixerInput) error {
+	if !input.Source.IsRunning() {
+		if err := input.Source.Start(); err != nil {
+			return fmt.Errorf("failed to start input '%s': %w", input.Name, err)
+		}
+	}
+	input.stop = make(chan struct{})
+	go m.readInput(input)
+	return nil
+}
+
+// stopInput stops an input's reader and source. Caller must hold m.mu.
+func (m *Mixer) stopInput(input *mixerInput) {
+	if input.stop != nil {
+		close(input.stop)
+		input.stop = nil
+	}
+	input.Source.Stop()
+}
+
+// readInput buffers frames from one source until it ends or is stopped
+func (m *Mixer) readInput(input *mixerInput) {
+	stop := input.stop
+	maxBuffered := mixerBufferFrames * m.config.FrameSize * input.channels
+
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		samples, err := input.Source.Read()
+		if errors.Is(err, io.EOF) {
+			select {
+			case m.ended <- input.Name:
+			default:
+				// Nobody is watching
+			}
+			return
+		}
+		if err != nil {
+			// Stopped source or a glitch: don't spin
+			select {
+			case <-stop:
+				return
+			case <-time.After(m.frameDuration()):
+			}
+			continue
+		}
+
+		samples = input.resampler.Resample(samples)
+
+		input.bufMu.Lock()
+		input.buf = append(input.buf, samples...)
+		full := len(input.buf) >= maxBuffered
+		input.bufMu.Unlock()
+
+		// Source runs ahead of the mixer clock: wait for the mixer instead of
+		// dropping audio, so unpaced sources are read at the frame rate
+		for full {
+			select {
+			case <-stop:
+				return
+			case <-input.space:
+			}
+			input.bufMu.Lock()
+			full = len(input.buf) >= maxBuffered
+			input.bufMu.Unlock()
+		}
+	}
+}
+
+// take removes one frame of samples, padded with silence on underrun
+func (in *mixerInput) take(frameSize int) []int16 {
+	frame := make([]int16, frameSize*in.channels)
+
+	in.bufMu.Lock()
+	n := copy(frame, in.buf)
+	in.buf = append(in.buf[:0], in.buf[n:]...)
+	in.bufMu.Unlock()
+
+	// Wake the reader if it waits for space
+	select {
+	case in.space <- struct{}{}:
+	default:
+	}
+
+	return frame
+}
+
+// panGains returns the left and right gains for a pan position
+// Mono inputs use a constant-power pan, stereo inputs a balance control.
+func panGains(pan float64, channels int) (float64, float64) {
+	if channels == 2 {
+		return math.Min(1, 1-pan), math.Min(1, 1+pan)
+	}
+	angle := (pan + 1) * math.Pi / 4
+	return math.Cos(angle), math.Sin(angle)
+}
+
+// levelDB returns the RMS level of a frame after gain, in dBFS
+func levelDB(frame []int16, gain float64) float64 {
+	if len(frame) == 0 {
+		return math.Inf(-1)
+	}
+	var sum float64
+	for _, s := range frame {
+		v := float64(s) * gain
+		sum += v * v
+	}
+	rms := math.Sqrt(sum / float64(len(frame)))
+	if rms == 0 {
+		return math.Inf(-1)
+	}
+	return 20 * math.Log10(rms/32768)
+}
+
+// dbToGain converts decibels to a linear gain
+func dbToGain(db float64) float64 {
+	return math.Pow(10, db/20)
+}
+
+// clampPan limits a pan position to -1..1
+func clampPan(pan float64) float64 {
+	return math.Max(-1, math.Min(1, pan))
+}
+
+// clampSample converts a mixed value to int16, clipping at full scale
+func clampSample(v float64) int16 {
+	if v > math.MaxInt16 {
+		return math.MaxInt16
+	}
+	if v < math.MinInt16 {
+		return math.MinInt16
+	}
+	return int16(v)
+}
@@ -0,0 +1,83 @@
+package audio
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SilenceSource produces silent frames in real time
+// Useful to keep a stream (and its listeners) alive while nothing plays.
+type SilenceSource struct {
+	config  StreamConfig
+	running bool
+	next    time.Time
+	mu      sync.Mutex
+}
+
+// NewSilenceSource creates a new silence source
+func NewSilenceSource(config StreamConfig) *SilenceSource {
+	return &SilenceSource{
+		config: config,
+	}
+}
+
+// Start starts the silence source
+func (s *SilenceSource) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running {
+		return fmt.Errorf("silence source already running")
+	}
+	s.running = true
+	s.next = time.Now()
+	return nil
+}
+
+// Stop stops the silence source
+func (s *SilenceSource) Stop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.running = false
+	return nil
+}
+
+// Read returns the next silent frame, waiting for its time
+func (s *SilenceSource) Read() ([]int16, error) {
+	s.mu.Lock()
+	if !s.running {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("silence source not running")
+	}
+	frameDuration := time.Duration(s.config.FrameSize) * time.Second / time.Duration(s.config.SampleRate)
+	wait := time.Until(s.next)
+	s.next = s.next.Add(frameDuration)
+	if wait < -10*frameDuration {
+		s.next = time.Now().Add(frameDuration)
+	}
+	s.mu.Unlock()
+
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+	return make([]int16, s.config.FrameSize*s.config.Channels), nil
+}
+
+// SampleRate returns the sample rate
+func (s *SilenceSource) SampleRate() int {
+	return s.config.SampleRate
+}
+
+// Channels returns the number of channels
+func (s *SilenceSource) Channels() int {
+	return s.config.Channels
+}
+
+// IsRunning returns whether the source is running
+func (s *SilenceSource) IsRunning() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running
+}
@@ -310,5 +310,10 @@ func (out *OutputStream) Write(frame []byte) error {
 	}
 }
 
+// BufferLevel returns the number of frames queued for playout and the buffer capacity
+func (out *OutputStream) BufferLevel() (queued, capacity int) {
+	return len(out.frames), cap(out.frames)
+}
+
 // Note: Playback is now handled by malgo's data callback in Start()
 // The callback reads from out.frames channel and copies data to the audio device
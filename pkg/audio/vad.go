@@ -0,0 +1,128 @@
+package audio
+
+import (
+	"math"
+	"time"
+)
+
+// Voice activity detection defaults
+const (
+	DefaultVADThreshold     = 9.0   // dB above the noise floor that counts as speech
+	DefaultVADMinLevel      = -55.0 // dBFS below which a frame is always silence
+	DefaultVADZeroCrossings = 0.15  // Zero crossings per sample of unvoiced speech (s, f, sh)
+	DefaultVADHangover      = 300 * time.Millisecond
+)
+
+// vadNoiseRise is how fast the noise floor estimate follows a louder background
+const vadNoiseRise = 2 * time.Second
+
+// VADConfig tunes voice activity detection
+type VADConfig struct {
+	Threshold     float64       // Optional: dB above the tracked noise floor that counts as speech (default: 9)
+	MinLevel      float64       // Optional: frames quieter than this dBFS are silence (default: -55)
+	ZeroCrossings float64       // Optional: zero-crossing rate that marks quiet unvoiced speech (default: 0.15)
+	Hangover      time.Duration // Optional: stay active this long after speech stops (default: 300ms)
+}
+
+// VAD detects speech in PCM frames from their energy and zero-crossing rate
+// Energy is compared to a tracked noise floor, so a steady background (fan,
+// hiss, wind) reads as silence. Quiet frames with many zero crossings still
+// count as speech: unvoiced sounds are weak but noisy. After speech stops the
+// VAD stays active for the hangover time so word endings and short pauses
+// are not clipped.
+type VAD struct {
+	config     VADConfig
+	channels   int
+	noiseFloor float64 // dBFS
+	noiseRise  float64 // Per-frame smoothing towards a louder floor
+	hangover   int     // Frames
+	hangLeft   int
+}
+
+// NewVAD creates a voice activity detector for frames of the given stream
+func NewVAD(cfg VADConfig, stream StreamConfig) *VAD {
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = DefaultVADThreshold
+	}
+	if cfg.MinLevel == 0 {
+		cfg.MinLevel = DefaultVADMinLevel
+	}
+	if cfg.ZeroCrossings <= 0 {
+		cfg.ZeroCrossings = DefaultVADZeroCrossings
+	}
+	if cfg.Hangover <= 0 {
+		cfg.Hangover = DefaultVADHangover
+	}
+
+	channels := stream.Channels
+	if channels < 1 {
+		channels = 1
+	}
+	frameDuration := 20 * time.Millisecond
+	if stream.SampleRate > 0 && stream.FrameSize > 0 {
+		frameDuration = time.Duration(stream.FrameSize) * time.Second / time.Duration(stream.SampleRate)
+	}
+
+	return &VAD{
+		config:     cfg,
+		channels:   channels,
+		noiseFloor: cfg.MinLevel, // Talking right away is not learned as background
+		noiseRise:  1 - math.Exp(-float64(frameDuration)/float64(vadNoiseRise)),
+		hangover:   int(cfg.Hangover / frameDuration),
+	}
+}
+
+// Active reports whether a frame holds speech, or follows speech within the
+// hangover time. Frames must be fed in order.
+func (v *VAD) Active(frame []int16) bool {
+	level := levelDB(frame, 1)
+
+	speech := false
+	if level > v.config.MinLevel {
+		above := level - v.noiseFloor
+		speech = above > v.config.Threshold ||
+			(above > v.config.Threshold/2 && v.zeroCrossingRate(frame) > v.config.ZeroCrossings)
+	}
+
+	// Track the background: drop at once, rise slowly. During speech it rises
+	// slower still, so a noise that starts and stays is learned; the pauses
+	// between words pull it back down to the real background.
+	floor := math.Max(level, v.config.MinLevel)
+	switch {
+	case floor < v.noiseFloor:
+		v.noiseFloor = floor
+	case speech:
+		v.noiseFloor += (floor - v.noiseFloor) * v.noiseRise / 4
+	default:
+		v.noiseFloor += (floor - v.noiseFloor) * v.noiseRise
+	}
+
+	if speech {
+		v.hangLeft = v.hangover
+		return true
+	}
+	if v.hangLeft > 0 {
+		v.hangLeft--
+		return true
+	}
+	return false
+}
+
+// zeroCrossingRate returns the sign changes per sample of the first channel
+func (v *VAD) zeroCrossingRate(frame []int16) float64 {
+	samples := len(frame) / v.channels
+	if samples < 2 {
+		return 0
+	}
+
+	crossings := 0
+	prev := frame[0]
+	for i := v.channels; i < len(frame); i += v.channels {
+		s := frame[i]
+		if (prev < 0) != (s < 0) {
+			crossings++
+		}
+		prev = s
+	}
+	return float64(crossings) / float64(samples-1)
+}
@@ -0,0 +1,417 @@
+package calling
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/meshradio/meshradio/internal/listener"
+	"github.com/meshradio/meshradio/pkg/audio"
+	"github.com/meshradio/meshradio/pkg/discovery"
+	"github.com/meshradio/meshradio/pkg/emergency"
+	"github.com/meshradio/meshradio/pkg/multicast"
+	"github.com/meshradio/meshradio/pkg/network"
+	"github.com/meshradio/meshradio/pkg/protocol"
+)
+
+// DefaultPort is the standard port call managers listen on
+// (just below the 8790-8799 channel range)
+const DefaultPort = 8789
+
+// Config holds call manager configuration
+type Config struct {
+	Callsign       string
+	IPv6           net.IP
+	Port           int             // Call port (default: 8789)
+	StreamPort     int             // Our broadcast port, advertised in outgoing calls (0 = not streaming)
+	Groups         []string        // Groups whose CQ calls we want (empty = all)
+	ListenerConfig listener.Config // Template for listeners started by Call.Accept
+}
+
+// Manager sends calls and raises events for incoming ones
+type Manager struct {
+	callsign    string
+	ipv6        net.IP
+	port        int
+	streamPort  int
+	groups      map[string]bool
+	listenerCfg listener.Config
+	transport   network.Transport
+	registry    *emergency.ChannelRegistry
+	nextCallID  uint32
+	running     bool
+	mu          sync.Mutex
+	stopChan    chan struct{}
+
+	// Events
+	incoming chan *Call
+	replies  chan Reply
+}
+
+// NewManager creates a new call manager
+func NewManager(cfg Config) (*Manager, error) {
+	port := cfg.Port
+	if port == 0 {
+		port = DefaultPort
+	}
+
+	transport, err := network.NewTransport(port)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transport: %w", err)
+	}
+
+	groups := make(map[string]bool)
+	for _, g := range cfg.Groups {
+		groups[g] = true
+	}
+
+	return &Manager{
+		callsign:    cfg.Callsign,
+		ipv6:        cfg.IPv6,
+		port:        port,
+		streamPort:  cfg.StreamPort,
+		groups:      groups,
+		listenerCfg: cfg.ListenerConfig,
+		transport:   transport,
+		registry:    emergency.NewChannelRegistry(),
+		nextCallID:  uint32(time.Now().UnixNano()),
+		stopChan:    make(chan struct{}),
+		incoming:    make(chan *Call, 10),
+		replies:     make(chan Reply, 10),
+	}, nil
+}
+
+// Start begins listening for calls
+func (m *Manager) Start() error {
+	m.mu.Lock()
+	if m.running {
+		m.mu.Unlock()
+		return fmt.Errorf("call manager already running")
+	}
+	m.running = true
+	m.mu.Unlock()
+
+	if err := m.transport.Start(); err != nil {
+		return fmt.Errorf("failed to start transport: %w", err)
+	}
+
+	go m.receiveLoop()
+
+	return nil
+}
+
+// Stop stops the call manager
+func (m *Manager) Stop() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.running {
+		return nil
+	}
+
+	m.running = false
+	close(m.stopChan)
+
+	return m.transport.Stop()
+}
+
+// Incoming returns the channel incoming calls are delivered on
+func (m *Manager) Incoming() <-chan *Call {
+	return m.incoming
+}
+
+// Replies returns the channel answers to our own calls are delivered on
+func (m *Manager) Replies() <-chan Reply {
+	return m.replies
+}
+
+// CQ sends a general call on a group to every target
+func (m *Manager) CQ(group, message string, targets []*net.UDPAddr) (uint32, error) {
+	if len(targets) == 0 {
+		return 0, fmt.Errorf("no targets for CQ on group '%s'", group)
+	}
+
+	callID := atomic.AddUint32(&m.nextCallID, 1)
+	packet := m.callPacket(protocol.PacketTypeCallCQ, &protocol.CallPayload{
+		CallID:     callID,
+		Group:      protocol.StringToGroup(group),
+		StreamPort: uint16(m.streamPort),
+		ReplyPort:  uint16(m.port),
+		Message:    message,
+	}, group)
+
+	sent := 0
+	for _, target := range targets {
+		if err := m.transport.Send(packet, target.IP, target.Port); err != nil {
+			fmt.Printf("⚠️  CQ to %s failed: %v\n", target, err)
+			continue
+		}
+		sent++
+	}
+
+	if sent == 0 {
+		return 0, fmt.Errorf("CQ could not be sent to any target")
+	}
+
+	fmt.Printf("📣 CQ CQ CQ de %s on '%s' (%d node(s))\n", m.callsign, group, sent)
+	return callID, nil
+}
+
+// CallStation sends a selective call to one station. The target node must
+// run a call manager at target; callsign may be empty to match by address only.
+func (m *Manager) CallStation(target *net.UDPAddr, callsign, group, message string) (uint32, error) {
+	var targetCallsign [16]byte
+	copy(targetCallsign[:], []byte(callsign))
+
+	callID := atomic.AddUint32(&m.nextCallID, 1)
+	packet := m.callPacket(protocol.PacketTypeCallSelective, &protocol.CallPayload{
+		CallID:         callID,
+		Group:          protocol.StringToGroup(group),
+		TargetCallsign: targetCallsign,
+		TargetIPv6:     protocol.IPv6ToBytes(target.IP),
+		StreamPort:     uint16(m.streamPort),
+		ReplyPort:      uint16(m.port),
+		Message:        message,
+	}, group)
+
+	if err := m.transport.Send(packet, target.IP, target.Port); err != nil {
+		return 0, fmt.Errorf("failed to send call: %w", err)
+	}
+
+	fmt.Printf("📞 %s de %s on '%s'\n", callsign, m.callsign, group)
+	return callID, nil
+}
+
+// callPacket builds a call packet with the group's channel priority
+func (m *Manager) callPacket(packetType uint8, cp *protocol.CallPayload, group string) *protocol.Packet {
+	packet := protocol.NewPacket(
+		packetType,
+		protocol.IPv6ToBytes(m.ipv6),
+		m.callsign,
+		protocol.MarshalCall(cp),
+	)
+
+	if ch, ok := m.registry.GetByGroup(group); ok {
+		packet.SetPriority(uint8(ch.Priority))
+	}
+	return packet
+}
+
+// receiveLoop dispatches incoming calls and replies
+func (m *Manager) receiveLoop() {
+	for {
+		select {
+		case <-m.stopChan:
+			return
+		default:
+		}
+
+		packet, from, err := m.transport.Receive()
+		if err != nil {
+			return // Transport closed
+		}
+
+		switch packet.Type {
+		case protocol.PacketTypeCallCQ, protocol.PacketTypeCallSelective:
+			m.handleCall(packet, from)
+		case protocol.PacketTypeCallReply:
+			m.handleReply(packet, from)
+		}
+	}
+}
+
+// handleCall raises an event for calls addressed to us
+func (m *Manager) handleCall(packet *protocol.Packet, from *net.UDPAddr) {
+	cp, err := protocol.UnmarshalCall(packet.Payload)
+	if err != nil {
+		fmt.Printf("Invalid call packet: %v\n", err)
+		return
+	}
+
+	callsign := packet.GetCallsign()
+	group := protocol.GetGroupString(cp.Group)
+
+	// Ignore our own calls
+	if callsign == m.callsign && from.IP.Equal(m.ipv6) {
+		return
+	}
+
+	call := &Call{
+		ID:         cp.CallID,
+		Type:       CallCQ,
+		From:       callsign,
+		FromIPv6:   from.IP, // Where the call really came from, not the claimed source
+		StreamPort: int(cp.StreamPort),
+		Group:      group,
+		Priority:   packet.GetPriority(),
+		Message:    cp.Message,
+		ReceivedAt: time.Now(),
+		replyAddr:  from,
+		manager:    m,
+	}
+
+	if packet.Type == protocol.PacketTypeCallSelective {
+		call.Type = CallSelective
+		if !m.isAddressedToUs(cp) {
+			return
+		}
+	} else if len(m.groups) > 0 && !m.groups[group] {
+		return
+	}
+
+	select {
+	case m.incoming <- call:
+	default:
+		fmt.Printf("⚠️  Incoming call queue full, dropping %s call from %s\n", call.Type, callsign)
+	}
+}
+
+// isAddressedToUs checks a selective call's target callsign and address
+func (m *Manager) isAddressedToUs(cp *protocol.CallPayload) bool {
+	target := protocol.GetCallsignString(cp.TargetCallsign)
+	if target != "" && strings.EqualFold(target, m.callsign) {
+		return true
+	}
+	if target == "" && !protocol.IsZeroIPv6(cp.TargetIPv6) {
+		return protocol.BytesToIPv6(cp.TargetIPv6).Equal(m.ipv6)
+	}
+	return false
+}
+
+// handleReply raises an event for answers to our calls
+func (m *Manager) handleReply(packet *protocol.Packet, from *net.UDPAddr) {
+	cr, err := protocol.UnmarshalCallReply(packet.Payload)
+	if err != nil {
+		fmt.Printf("Invalid call reply: %v\n", err)
+		return
+	}
+
+	reply := Reply{
+		CallID:     cr.CallID,
+		From:       packet.GetCallsign(),
+		FromIPv6:   from.IP,
+		Accepted:   cr.Result == protocol.CallAccepted,
+		ReceivedAt: time.Now(),
+	}
+
+	select {
+	case m.replies <- reply:
+	default:
+	}
+}
+
+// sendReply answers a call
+func (m *Manager) sendReply(c *Call, result uint8) error {
+	packet := protocol.NewPacket(
+		protocol.PacketTypeCallReply,
+		protocol.IPv6ToBytes(m.ipv6),
+		m.callsign,
+		protocol.MarshalCallReply(&protocol.CallReplyPayload{
+			CallID: c.ID,
+			Result: result,
+		}),
+	)
+
+	if err := m.transport.Send(packet, c.replyAddr.IP, c.replyAddr.Port); err != nil {
+		return fmt.Errorf("failed to send call reply: %w", err)
+	}
+	return nil
+}
+
+// Accept answers the call and starts a listener on the caller's stream.
+// If the caller is not streaming, the call is acknowledged and no listener is returned.
+func (c *Call) Accept() (*listener.Listener, error) {
+	if err := c.manager.sendReply(c, protocol.CallAccepted); err != nil {
+		return nil, err
+	}
+
+	if c.StreamPort == 0 {
+		return nil, nil
+	}
+
+	cfg := c.manager.listenerCfg
+	if cfg.Callsign == "" {
+		cfg.Callsign = c.manager.callsign
+	}
+	if cfg.LocalIPv6 == nil {
+		cfg.LocalIPv6 = c.manager.ipv6
+	}
+	if cfg.LocalPort == 0 {
+		cfg.LocalPort = c.StreamPort + 1000 // Same convention as emergency-test
+	}
+	if cfg.AudioConfig.SampleRate == 0 {
+		cfg.AudioConfig = audio.DefaultConfig()
+	}
+	cfg.TargetIPv6 = c.FromIPv6
+	cfg.TargetPort = c.StreamPort
+	cfg.Group = c.Group
+	cfg.SSMSource = c.FromIPv6 // Only the caller's stream
+
+	l, err := listener.New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create listener: %w", err)
+	}
+
+	if err := l.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start listener: %w", err)
+	}
+
+	return l, nil
+}
+
+// Decline answers the call negatively
+func (c *Call) Decline() error {
+	return c.manager.sendReply(c, protocol.CallDeclined)
+}
+
+// TargetsFromCache returns the call port of every node hosting a station in
+// the group (empty = all groups), deduplicated by address
+func TargetsFromCache(cache *discovery.Cache, group string) []*net.UDPAddr {
+	seen := make(map[string]bool)
+	targets := make([]*net.UDPAddr, 0)
+
+	for _, r := range cache.List(group) {
+		if r.IPv6 == nil {
+			continue
+		}
+		key := fmt.Sprintf("%x", r.IPv6.To16())
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		targets = append(targets, &net.UDPAddr{IP: r.IPv6, Port: DefaultPort})
+	}
+
+	return targets
+}
+
+// TargetsFromSubscribers returns the call port of every subscriber
+func TargetsFromSubscribers(subs []*multicast.Subscriber) []*net.UDPAddr {
+	targets := make([]*net.UDPAddr, 0, len(subs))
+	for _, sub := range subs {
+		targets = append(targets, &net.UDPAddr{IP: sub.IPv6, Port: DefaultPort})
+	}
+	return targets
+}
+
+// ResolveCallsign finds a station's address in the discovery cache
+func ResolveCallsign(cache *discovery.Cache, callsign string) (*net.UDPAddr, bool) {
+	for _, r := range cache.List("") {
+		if strings.EqualFold(r.Callsign, callsign) && r.IPv6 != nil {
+			return &net.UDPAddr{IP: r.IPv6, Port: DefaultPort}, true
+		}
+	}
+	return nil, false
+}
+
+// ParseTarget parses a call target ("[ipv6]:port" or bare ipv6, which uses DefaultPort)
+func ParseTarget(s string) (*net.UDPAddr, error) {
+	s = strings.TrimSpace(s)
+	if ip := net.ParseIP(strings.Trim(s, "[]")); ip != nil {
+		return &net.UDPAddr{IP: ip, Port: DefaultPort}, nil
+	}
+	return discovery.ParseSeed(s)
+}
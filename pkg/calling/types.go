@@ -0,0 +1,52 @@
+package calling
+
+import (
+	"net"
+	"time"
+)
+
+// CallType distinguishes general (CQ) from selective calls
+type CallType int
+
+const (
+	CallCQ        CallType = iota // General call to everyone on a group
+	CallSelective                 // Call to one specific station
+)
+
+// String returns the string representation of the call type
+func (t CallType) String() string {
+	switch t {
+	case CallCQ:
+		return "CQ"
+	case CallSelective:
+		return "selective"
+	default:
+		return "unknown"
+	}
+}
+
+// Call is an incoming call raised by the Manager
+// Answer it with Accept or Decline
+type Call struct {
+	ID         uint32
+	Type       CallType
+	From       string // Caller callsign
+	FromIPv6   net.IP
+	StreamPort int    // Caller's broadcast port (0 = caller is not streaming)
+	Group      string // Group the caller is on
+	Priority   uint8  // 0-3, from the packet header
+	Message    string
+	ReceivedAt time.Time
+
+	replyAddr *net.UDPAddr // Sender address of the call packet
+	manager   *Manager
+}
+
+// Reply is an answer to one of our own calls
+type Reply struct {
+	CallID     uint32
+	From       string
+	FromIPv6   net.IP
+	Accepted   bool
+	ReceivedAt time.Time
+}
@@ -0,0 +1,94 @@
+package discovery
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Cache holds discovered stations, deduplicated by address, port and group
+type Cache struct {
+	records map[string]Record // Key: Record.Key()
+	mu      sync.RWMutex
+}
+
+// NewCache creates an empty discovery cache
+func NewCache() *Cache {
+	return &Cache{
+		records: make(map[string]Record),
+	}
+}
+
+// Add inserts or refreshes a record
+// When the same station is reported twice, the closer (fewer hops) report
+// wins, and equal-distance reports extend the expiry
+func (c *Cache) Add(r Record) {
+	if r.Remaining() <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := r.Key()
+	existing, ok := c.records[key]
+	if ok && existing.Remaining() > 0 {
+		if r.Hops > existing.Hops {
+			return
+		}
+		if r.Hops == existing.Hops && r.Expires.Before(existing.Expires) {
+			return
+		}
+	}
+	c.records[key] = r
+}
+
+// List returns unexpired records, optionally filtered by group (empty = all),
+// sorted by callsign
+func (c *Cache) List(group string) []Record {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := time.Now()
+	records := make([]Record, 0, len(c.records))
+	for _, r := range c.records {
+		if !r.Expires.After(now) {
+			continue
+		}
+		if group != "" && r.Group != group {
+			continue
+		}
+		records = append(records, r)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Callsign != records[j].Callsign {
+			return records[i].Callsign < records[j].Callsign
+		}
+		return records[i].Group < records[j].Group
+	})
+	return records
+}
+
+// Prune removes expired records and returns how many were removed
+func (c *Cache) Prune() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	count := 0
+	now := time.Now()
+	for key, r := range c.records {
+		if !r.Expires.After(now) {
+			delete(c.records, key)
+			count++
+		}
+	}
+	return count
+}
+
+// Len returns the number of cached records (including expired ones not yet pruned)
+func (c *Cache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.records)
+}
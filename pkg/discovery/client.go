@@ -0,0 +1,248 @@
+package discovery
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/meshradio/meshradio/pkg/emergency"
+	"github.com/meshradio/meshradio/pkg/network"
+	"github.com/meshradio/meshradio/pkg/protocol"
+	"github.com/meshradio/meshradio/pkg/yggdrasil"
+)
+
+// DefaultPort is assumed for seeds given without a port
+const DefaultPort = 8799
+
+// BrowseOptions configures a mesh discovery query
+type BrowseOptions struct {
+	Seeds   []*net.UDPAddr // Nodes to ask directly
+	Peers   bool           // Also ask directly connected Yggdrasil peers on the standard channel ports
+	Group   string         // Filter by group (empty = all)
+	Timeout time.Duration  // How long to wait for responses (default: 3 seconds)
+}
+
+// Client queries mesh nodes for the stations they host or have heard of
+type Client struct {
+	ipv6      net.IP
+	transport network.Transport
+	cache     *Cache
+	requestID uint32
+	responses chan struct{}
+	stopChan  chan struct{}
+	closeOnce sync.Once
+}
+
+// NewClient creates a discovery client bound to localPort (0 = any free port).
+// Results are merged into cache; if nil, a private cache is created.
+func NewClient(ipv6 net.IP, localPort int, cache *Cache) (*Client, error) {
+	transport, err := network.NewTransport(localPort)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transport: %w", err)
+	}
+
+	if err := transport.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start transport: %w", err)
+	}
+
+	if cache == nil {
+		cache = NewCache()
+	}
+
+	c := &Client{
+		ipv6:      ipv6,
+		transport: transport,
+		cache:     cache,
+		responses: make(chan struct{}, 64),
+		stopChan:  make(chan struct{}),
+	}
+
+	go c.receiveLoop()
+
+	return c, nil
+}
+
+// Close stops the client
+func (c *Client) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.stopChan)
+		err = c.transport.Stop()
+	})
+	return err
+}
+
+// Cache returns the cache results are merged into
+func (c *Client) Cache() *Cache {
+	return c.cache
+}
+
+// Browse asks the seeds (and optionally Yggdrasil peers) for stations and
+// returns the merged, deduplicated cache contents
+func (c *Client) Browse(opts BrowseOptions) ([]Record, error) {
+	// Set default timeout
+	if opts.Timeout == 0 {
+		opts.Timeout = 3 * time.Second
+	}
+
+	targets := append([]*net.UDPAddr{}, opts.Seeds...)
+	if opts.Peers {
+		peerTargets, err := peerAddrs()
+		if err != nil {
+			fmt.Printf("⚠️  Could not list Yggdrasil peers: %v\n", err)
+		}
+		targets = append(targets, peerTargets...)
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no seeds or peers to query")
+	}
+
+	// New request ID so late answers to earlier queries are ignored
+	requestID := atomic.AddUint32(&c.requestID, 1)
+	c.drainResponses()
+
+	req := &protocol.DiscoveryRequestPayload{
+		RequestID: requestID,
+		ReplyIPv6: protocol.IPv6ToBytes(c.ipv6),
+		ReplyPort: uint16(c.transport.LocalAddr().Port),
+		Group:     protocol.StringToGroup(opts.Group),
+	}
+
+	packet := protocol.NewPacket(
+		protocol.PacketTypeDiscoveryReq,
+		protocol.IPv6ToBytes(c.ipv6),
+		"",
+		protocol.MarshalDiscoveryRequest(req),
+	)
+
+	sent := 0
+	for _, target := range targets {
+		if err := c.transport.Send(packet, target.IP, target.Port); err != nil {
+			fmt.Printf("⚠️  Discovery request to %s failed: %v\n", target, err)
+			continue
+		}
+		sent++
+	}
+
+	// Wait for the timeout, or until every target answered
+	deadline := time.After(opts.Timeout)
+	for answered := 0; answered < sent; {
+		select {
+		case <-c.responses:
+			answered++
+		case <-deadline:
+			answered = sent
+		case <-c.stopChan:
+			return nil, fmt.Errorf("discovery client closed")
+		}
+	}
+
+	c.cache.Prune()
+	return c.cache.List(opts.Group), nil
+}
+
+// receiveLoop merges discovery responses into the cache
+func (c *Client) receiveLoop() {
+	for {
+		select {
+		case <-c.stopChan:
+			return
+		default:
+		}
+
+		packet, from, err := c.transport.Receive()
+		if err != nil {
+			return // Transport closed
+		}
+
+		if packet.Type != protocol.PacketTypeDiscoveryResp {
+			continue
+		}
+
+		resp, err := protocol.UnmarshalDiscoveryResponse(packet.Payload)
+		if err != nil {
+			fmt.Printf("Invalid discovery response: %v\n", err)
+			continue
+		}
+
+		if resp.RequestID != atomic.LoadUint32(&c.requestID) {
+			continue // Stale answer to an earlier query
+		}
+
+		via := from.IP // The node that actually answered, not what it claims
+		for _, sr := range resp.Records {
+			c.cache.Add(RecordFromStation(sr, via))
+		}
+
+		select {
+		case c.responses <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// drainResponses discards response notifications left over from a previous query
+func (c *Client) drainResponses() {
+	for {
+		select {
+		case <-c.responses:
+		default:
+			return
+		}
+	}
+}
+
+// ParseSeed parses "[ipv6]:port" or a bare IPv6 address (DefaultPort is assumed)
+func ParseSeed(s string) (*net.UDPAddr, error) {
+	s = strings.TrimSpace(s)
+
+	if ip := net.ParseIP(strings.Trim(s, "[]")); ip != nil {
+		return &net.UDPAddr{IP: ip, Port: DefaultPort}, nil
+	}
+
+	host, portStr, err := net.SplitHostPort(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid seed %q: %w", s, err)
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid seed address: %s", host)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port <= 0 || port > 65535 {
+		return nil, fmt.Errorf("invalid seed port: %s", portStr)
+	}
+
+	return &net.UDPAddr{IP: ip, Port: port}, nil
+}
+
+// peerAddrs returns the standard channel ports of every directly connected Yggdrasil peer
+func peerAddrs() ([]*net.UDPAddr, error) {
+	peers, err := yggdrasil.NewClient().GetPeers()
+	if err != nil {
+		return nil, err
+	}
+
+	ports := make(map[int]bool)
+	for _, ch := range emergency.StandardChannels {
+		ports[ch.Port] = true
+	}
+
+	addrs := make([]*net.UDPAddr, 0)
+	for _, peer := range peers {
+		ip := net.ParseIP(strings.Trim(peer, "[]"))
+		if ip == nil {
+			continue
+		}
+		for port := range ports {
+			addrs = append(addrs, &net.UDPAddr{IP: ip, Port: port})
+		}
+	}
+
+	return addrs, nil
+}
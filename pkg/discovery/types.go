@@ -0,0 +1,133 @@
+package discovery
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/meshradio/meshradio/pkg/emergency"
+	"github.com/meshradio/meshradio/pkg/mdns"
+	"github.com/meshradio/meshradio/pkg/protocol"
+)
+
+// DefaultTTL is how long peers may cache a record for a station we host
+const DefaultTTL = 60 * time.Second
+
+// Record is a station learned through mesh discovery
+// It carries the same fields as an mDNS ServiceInfo plus cache bookkeeping
+type Record struct {
+	mdns.ServiceInfo
+
+	TTL     time.Duration // TTL advertised by the responder
+	Expires time.Time     // When this record must be dropped from the cache
+	Via     net.IP        // Node that told us about the station
+	Hops    int           // 0 = Via hosts the station itself
+}
+
+// Key returns the deduplication key (station address, port and group)
+func (r Record) Key() string {
+	return fmt.Sprintf("%x:%d:%s", r.IPv6.To16(), r.Port, r.Group)
+}
+
+// Remaining returns the time left before the record expires
+func (r Record) Remaining() time.Duration {
+	return time.Until(r.Expires)
+}
+
+// RecordFromStation converts a wire record received from via
+func RecordFromStation(sr protocol.StationRecord, via net.IP) Record {
+	callsign := protocol.GetCallsignString(sr.Callsign)
+	group := protocol.GetGroupString(sr.Group)
+	ttl := time.Duration(sr.TTL) * time.Second
+
+	return Record{
+		ServiceInfo: mdns.ServiceInfo{
+			Name:     callsign,
+			Port:     int(sr.Port),
+			IPv6:     protocol.BytesToIPv6(sr.IPv6),
+			Group:    group,
+			Channel:  group,
+			Callsign: callsign,
+			Priority: emergency.Priority(sr.Priority).String(),
+			Codec:    codecName(sr.CodecType),
+			Bitrate:  int(sr.Bitrate),
+		},
+		TTL:     ttl,
+		Expires: time.Now().Add(ttl),
+		Via:     via,
+		Hops:    int(sr.Hops),
+	}
+}
+
+// ToStation converts the record back to wire format for re-advertising.
+// The TTL is the remaining lifetime so records can't outlive their origin.
+func (r Record) ToStation() protocol.StationRecord {
+	var callsign [16]byte
+	copy(callsign[:], []byte(r.Callsign))
+
+	ttl := r.Remaining() / time.Second
+	if ttl < 0 {
+		ttl = 0
+	}
+	if ttl > 0xFFFF {
+		ttl = 0xFFFF
+	}
+
+	hops := r.Hops
+	if hops > 0xFF {
+		hops = 0xFF
+	}
+
+	return protocol.StationRecord{
+		IPv6:      protocol.IPv6ToBytes(r.IPv6),
+		Port:      uint16(r.Port),
+		Callsign:  callsign,
+		Group:     protocol.StringToGroup(r.Group),
+		Priority:  uint8(emergency.ParsePriority(r.Priority)),
+		CodecType: codecType(r.Codec),
+		Bitrate:   uint16(r.Bitrate),
+		TTL:       uint16(ttl),
+		Hops:      uint8(hops),
+	}
+}
+
+// FormatRecord returns a human-readable string for a Record
+func FormatRecord(r Record) string {
+	via := "direct"
+	if r.Hops > 0 && r.Via != nil {
+		via = fmt.Sprintf("via %s, %d hop(s)", r.Via, r.Hops)
+	}
+	return fmt.Sprintf("%s | ttl %s | %s",
+		mdns.FormatServiceInfo(r.ServiceInfo), r.Remaining().Round(time.Second), via)
+}
+
+// codecName maps protocol codec types to the names used in mDNS TXT records
+func codecName(codec uint8) string {
+	switch codec {
+	case protocol.CodecOpus:
+		return "opus"
+	case protocol.CodecFLAC:
+		return "flac"
+	case protocol.CodecAAC:
+		return "aac"
+	case protocol.CodecMP3:
+		return "mp3"
+	default:
+		return "unknown"
+	}
+}
+
+// codecType maps codec names back to protocol codec types
+func codecType(name string) uint8 {
+	switch strings.ToLower(name) {
+	case "flac":
+		return protocol.CodecFLAC
+	case "aac":
+		return protocol.CodecAAC
+	case "mp3":
+		return protocol.CodecMP3
+	default:
+		return protocol.CodecOpus
+	}
+}
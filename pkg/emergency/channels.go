@@ -7,6 +7,7 @@ type Channel struct {
 	Port        int          // RTP port
 	Priority    Priority     // Default priority
 	AutoTune    AutoTuneMode // Default auto-tune behavior
+	Voice       bool         // Speech channel: silence is not transmitted (DTX)
 	Description string       // Human-readable description
 }
 
@@ -32,6 +33,7 @@ var StandardChannels = map[string]Channel{
 		Port:        PortEmergency,
 		Priority:    PriorityCritical,
 		AutoTune:    AutoTuneAlways,
+		Voice:       true,
 		Description: "General emergency broadcast - active emergency in progress",
 	},
 	"netcontrol": {
@@ -40,6 +42,7 @@ var StandardChannels = map[string]Channel{
 		Port:        PortNetControl,
 		Priority:    PriorityEmergency,
 		AutoTune:    AutoTunePrompt,
+		Voice:       true,
 		Description: "Emergency net control - coordination and resource management",
 	},
 	"medical": {
@@ -80,6 +83,7 @@ var StandardChannels = map[string]Channel{
 		Port:        PortTalk,
 		Priority:    PriorityNormal,
 		AutoTune:    AutoTuneNever,
+		Voice:       true,
 		Description: "General conversation - casual communication",
 	},
 	"test": {
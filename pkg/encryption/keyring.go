@@ -0,0 +1,275 @@
+package encryption
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/meshradio/meshradio/pkg/protocol"
+)
+
+// KeySize is the size of a group key (AES-256)
+const KeySize = 32
+
+// Encrypted payload layout: [keyID:1][nonce:12][AES-GCM ciphertext + tag]
+const (
+	nonceSize = 12
+	overhead  = 1 + nonceSize + 16
+)
+
+// passphraseIterations is the PBKDF2 cost for passphrase-derived keys
+const passphraseIterations = 100000
+
+var (
+	ErrUnknownKey    = errors.New("no key for encrypted packet")
+	ErrDecryptFailed = errors.New("failed to decrypt packet")
+	ErrNoGroupKey    = errors.New("no key for group")
+)
+
+// groupKeys holds the keys of one group
+type groupKeys struct {
+	keys   map[uint8]cipher.AEAD
+	active uint8 // Key id used for sending
+}
+
+// Keyring holds the symmetric keys of private groups.
+// Each group may have several keys, identified by a key id, so keys can be
+// rotated: listeners add the new key first, then the broadcaster switches to it.
+type Keyring struct {
+	groups map[string]*groupKeys
+	mu     sync.RWMutex
+}
+
+// NewKeyring creates an empty keyring
+func NewKeyring() *Keyring {
+	return &Keyring{
+		groups: make(map[string]*groupKeys),
+	}
+}
+
+// DeriveKey derives a group key from a pre-shared passphrase.
+// The group name is the salt, so one passphrase gives different keys per group.
+func DeriveKey(passphrase, group string) ([]byte, error) {
+	return pbkdf2.Key(sha256.New, passphrase, []byte("meshradio:"+group), passphraseIterations, KeySize)
+}
+
+// AddKey adds a key for a group and makes it the active (sending) key
+func (k *Keyring) AddKey(group string, keyID uint8, key []byte) error {
+	if len(key) != KeySize {
+		return fmt.Errorf("group key must be %d bytes, got %d", KeySize, len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return fmt.Errorf("failed to create AEAD: %w", err)
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	gk, ok := k.groups[group]
+	if !ok {
+		gk = &groupKeys{keys: make(map[uint8]cipher.AEAD)}
+		k.groups[group] = gk
+	}
+	gk.keys[keyID] = aead
+	gk.active = keyID
+
+	return nil
+}
+
+// AddPassphrase derives a key from a passphrase and adds it (see AddKey)
+func (k *Keyring) AddPassphrase(group string, keyID uint8, passphrase string) error {
+	key, err := DeriveKey(passphrase, group)
+	if err != nil {
+		return fmt.Errorf("failed to derive key: %w", err)
+	}
+	return k.AddKey(group, keyID, key)
+}
+
+// SetActive selects which of a group's keys is used for sending
+func (k *Keyring) SetActive(group string, keyID uint8) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	gk, ok := k.groups[group]
+	if !ok {
+		return ErrNoGroupKey
+	}
+	if _, ok := gk.keys[keyID]; !ok {
+		return fmt.Errorf("group '%s' has no key %d", group, keyID)
+	}
+	gk.active = keyID
+	return nil
+}
+
+// RemoveKey retires one of a group's keys
+func (k *Keyring) RemoveKey(group string, keyID uint8) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if gk, ok := k.groups[group]; ok {
+		delete(gk.keys, keyID)
+		if len(gk.keys) == 0 {
+			delete(k.groups, group)
+		}
+	}
+}
+
+// IsPrivate reports whether the keyring has keys for a group
+func (k *Keyring) IsPrivate(group string) bool {
+	if k == nil {
+		return false
+	}
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	_, ok := k.groups[group]
+	return ok
+}
+
+// Seal encrypts the packet payload with the group's active key and sets FlagEncrypted.
+// Call before signing; the header fields identifying the stream are authenticated.
+func (k *Keyring) Seal(group string, packet *protocol.Packet) error {
+	k.mu.RLock()
+	gk, ok := k.groups[group]
+	var aead cipher.AEAD
+	var keyID uint8
+	if ok {
+		keyID = gk.active
+		aead = gk.keys[keyID]
+	}
+	k.mu.RUnlock()
+
+	if aead == nil {
+		return ErrNoGroupKey
+	}
+
+	buf := make([]byte, 1+nonceSize, len(packet.Payload)+overhead)
+	buf[0] = keyID
+	if _, err := rand.Read(buf[1 : 1+nonceSize]); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	buf = aead.Seal(buf, buf[1:1+nonceSize], packet.Payload, additionalData(packet))
+
+	packet.Payload = buf
+	packet.PayloadLength = uint16(len(buf))
+	packet.Flags |= protocol.FlagEncrypted
+	return nil
+}
+
+// Open decrypts an encrypted packet payload in place and clears FlagEncrypted.
+// Returns ErrUnknownKey when the packet uses a key id we don't hold.
+func (k *Keyring) Open(group string, packet *protocol.Packet) error {
+	if len(packet.Payload) < overhead {
+		return ErrDecryptFailed
+	}
+	keyID := packet.Payload[0]
+
+	var aead cipher.AEAD
+	if k != nil {
+		k.mu.RLock()
+		if gk, ok := k.groups[group]; ok {
+			aead = gk.keys[keyID]
+		}
+		k.mu.RUnlock()
+	}
+	if aead == nil {
+		return ErrUnknownKey
+	}
+
+	nonce := packet.Payload[1 : 1+nonceSize]
+	plain, err := aead.Open(nil, nonce, packet.Payload[1+nonceSize:], additionalData(packet))
+	if err != nil {
+		return ErrDecryptFailed
+	}
+
+	packet.Payload = plain
+	packet.PayloadLength = uint16(len(plain))
+	packet.Flags &^= protocol.FlagEncrypted
+	return nil
+}
+
+// KeyID returns the key id of an encrypted packet (for logging)
+func KeyID(packet *protocol.Packet) uint8 {
+	if len(packet.Payload) == 0 {
+		return 0
+	}
+	return packet.Payload[0]
+}
+
+// additionalData binds the ciphertext to the stream it was sent on
+func additionalData(packet *protocol.Packet) []byte {
+	ad := make([]byte, 0, 1+16+16+4+4)
+	ad = append(ad, packet.Type)
+	ad = append(ad, packet.SourceIPv6[:]...)
+	ad = append(ad, packet.Callsign[:]...)
+	ad = binary.BigEndian.AppendUint32(ad, packet.StreamID)
+	ad = binary.BigEndian.AppendUint32(ad, packet.SequenceNum)
+	return ad
+}
+
+// LoadKeyring reads a keyring file.
+// Each line is "GROUP KEYID KEY" where KEY is 64 hex characters or a passphrase
+// (runs of spaces in a passphrase are collapsed to one). The last key listed for a group is the active one.
+// Blank lines and # comments are ignored.
+func LoadKeyring(path string) (*Keyring, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open keyring: %w", err)
+	}
+	defer f.Close()
+
+	k := NewKeyring()
+	scanner := bufio.NewScanner(f)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("%s:%d: expected \"GROUP KEYID KEY\"", path, lineNum)
+		}
+		group := fields[0]
+		secret := strings.Join(fields[2:], " ")
+
+		keyID, err := strconv.ParseUint(fields[1], 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid key id %q", path, lineNum, fields[1])
+		}
+
+		raw, hexErr := hex.DecodeString(secret)
+		if hexErr == nil && len(raw) == KeySize {
+			err = k.AddKey(group, uint8(keyID), raw)
+		} else {
+			err = k.AddPassphrase(group, uint8(keyID), secret)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNum, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read keyring: %w", err)
+	}
+
+	return k, nil
+}
@@ -15,6 +15,8 @@ import (
 	"github.com/meshradio/meshradio/internal/broadcaster"
 	"github.com/meshradio/meshradio/internal/listener"
 	"github.com/meshradio/meshradio/pkg/audio"
+	"github.com/meshradio/meshradio/pkg/network"
+	"github.com/meshradio/meshradio/pkg/protocol"
 )
 
 //go:embed web/*
@@ -49,6 +51,111 @@ type StatusUpdate struct {
 	Station     string `json:"station,omitempty"`
 	PacketCount uint64 `json:"packetCount"`
 	SignalQuality uint8 `json:"signalQuality"`
+	NowPlaying  *NowPlaying `json:"nowPlaying,omitempty"`
+	OffAir      *OffAir     `json:"offAir,omitempty"`
+	Listeners   []ListenerReception `json:"listeners,omitempty"`
+	Traffic     *Traffic            `json:"traffic,omitempty"`
+}
+
+// Traffic summarizes the active transport's counters
+type Traffic struct {
+	PacketsSent     uint64 `json:"packetsSent"`
+	PacketsReceived uint64 `json:"packetsReceived"`
+	BytesSent       uint64 `json:"bytesSent"`
+	BytesReceived   uint64 `json:"bytesReceived"`
+	Dropped         uint64 `json:"dropped"` // Receive queue full
+	Malformed       uint64 `json:"malformed"`
+	WriteErrors     uint64 `json:"writeErrors"`
+	Peers           int    `json:"peers"`
+}
+
+// newTraffic converts transport stats for the GUI
+func newTraffic(ts network.TransportStats) *Traffic {
+	return &Traffic{
+		PacketsSent:     ts.PacketsSent,
+		PacketsReceived: ts.PacketsReceived,
+		BytesSent:       ts.BytesSent,
+		BytesReceived:   ts.BytesReceived,
+		Dropped:         ts.DroppedFull,
+		Malformed:       ts.Malformed,
+		WriteErrors:     ts.WriteErrors,
+		Peers:           len(ts.Peers),
+	}
+}
+
+// ListenerReception is one row of the broadcaster's per-listener reception table
+type ListenerReception struct {
+	Callsign    string  `json:"callsign"`
+	Address     string  `json:"address"`
+	Received    uint64  `json:"received"`
+	Lost        uint64  `json:"lost"`
+	LossPercent float64 `json:"lossPercent"` // Latest report interval
+	JitterMs    float64 `json:"jitterMs"`
+	Buffer      int     `json:"buffer"` // Playout buffer fill, percent
+	Errors      uint64  `json:"errors"`
+	Quality     uint8   `json:"quality"`
+	LastReport  int64   `json:"lastReport"` // Seconds ago
+}
+
+// newListenerReceptions converts broadcaster signal reports for the GUI
+func newListenerReceptions(reports []broadcaster.ListenerReport) []ListenerReception {
+	rows := make([]ListenerReception, 0, len(reports))
+	for _, r := range reports {
+		buffer := 0
+		if r.BufferCapacity > 0 {
+			buffer = r.BufferLevel * 100 / r.BufferCapacity
+		}
+		rows = append(rows, ListenerReception{
+			Callsign:    r.Callsign,
+			Address:     fmt.Sprintf("[%s]:%d", r.IPv6, r.Port),
+			Received:    r.PacketsReceived,
+			Lost:        r.PacketsLost,
+			LossPercent: r.LossRate * 100,
+			JitterMs:    float64(r.Jitter) / float64(time.Millisecond),
+			Buffer:      buffer,
+			Errors:      r.DecodeErrors,
+			Quality:     r.Quality,
+			LastReport:  int64(time.Since(r.LastReport) / time.Second),
+		})
+	}
+	return rows
+}
+
+// NowPlaying is the station's current track as shown in the GUI
+type NowPlaying struct {
+	Title       string `json:"title,omitempty"`
+	Artist      string `json:"artist,omitempty"`
+	Album       string `json:"album,omitempty"`
+	Duration    int    `json:"duration"` // Seconds (0 = unknown)
+	Position    int    `json:"position"` // Seconds
+	StationText string `json:"stationText,omitempty"`
+}
+
+// newNowPlaying converts protocol metadata for the GUI
+func newNowPlaying(md protocol.Metadata) *NowPlaying {
+	return &NowPlaying{
+		Title:       md.Title,
+		Artist:      md.Artist,
+		Album:       md.Album,
+		Duration:    int(md.Duration / time.Second),
+		Position:    int(md.Position / time.Second),
+		StationText: md.StationText,
+	}
+}
+
+// OffAir tells the GUI the tuned station signed off
+type OffAir struct {
+	Reason string `json:"reason"`
+	BackAt int64  `json:"backAt,omitempty"` // Unix seconds (0 = unknown)
+}
+
+// newOffAir converts the listener's off-air state for the GUI
+func newOffAir(info listener.OffAirInfo) *OffAir {
+	oa := &OffAir{Reason: info.ReasonString()}
+	if !info.BackAt.IsZero() {
+		oa.BackAt = info.BackAt.Unix()
+	}
+	return oa
 }
 
 // NewServer creates a new web GUI server
@@ -155,6 +262,11 @@ func (s *Server) getStatus() StatusUpdate {
 
 	if s.broadcaster != nil && s.broadcaster.IsRunning() {
 		status.Mode = "broadcasting"
+		status.Listeners = newListenerReceptions(s.broadcaster.GetListenerReports())
+		status.Traffic = newTraffic(s.broadcaster.GetTransportStats())
+		if md, ok := s.broadcaster.GetMetadata(); ok {
+			status.NowPlaying = newNowPlaying(md)
+		}
 	} else if s.listener != nil && s.listener.IsRunning() {
 		status.Mode = "listening"
 		packets, _, station := s.listener.GetStats()
@@ -169,6 +281,14 @@ func (s *Server) getStatus() StatusUpdate {
 			status.Station = station
 		}
 		status.PacketCount = packets
+		status.SignalQuality = s.listener.GetReceptionStats().Quality
+		status.Traffic = newTraffic(s.listener.GetTransportStats())
+		if md, ok := s.listener.GetMetadata(); ok {
+			status.NowPlaying = newNowPlaying(md)
+		}
+		if info, ok := s.listener.OffAir(); ok {
+			status.OffAir = newOffAir(info)
+		}
 	}
 
 	return status
@@ -185,19 +185,145 @@ class MeshRadioGUI {
         if (status.mode === 'broadcasting') {
             modeBadge.classList.add('broadcasting');
             document.getElementById('broadcast-addr').textContent = status.ipv6 + ':9001';
+            this.updateReception(status.listeners || []);
         } else if (status.mode === 'listening') {
             modeBadge.classList.add('listening');
             document.getElementById('station-name').textContent = status.station || 'Unknown';
             document.getElementById('packet-count').textContent = status.packetCount || 0;
+            this.updateNowPlaying(status.nowPlaying);
+            this.updateOffAir(status.offAir);
 
-            // Update signal strength
-            const signalPercent = Math.min((status.packetCount % 100), 100);
-            document.getElementById('signal-strength').style.width = signalPercent + '%';
+            // Update signal strength (quality from the latest signal report)
+            document.getElementById('signal-strength').style.width = (status.signalQuality || 0) + '%';
         }
 
+        this.updateTraffic(status.traffic);
+
         this.mode = status.mode;
     }
 
+    updateTraffic(traffic) {
+        const el = document.getElementById('traffic');
+
+        if (!traffic) {
+            el.textContent = '-';
+            el.title = '';
+            return;
+        }
+
+        let text = `↑${traffic.packetsSent} ↓${traffic.packetsReceived}`;
+        const problems = traffic.dropped + traffic.malformed + traffic.writeErrors;
+        if (problems > 0) {
+            text += ` ⚠ ${problems}`;
+        }
+        el.textContent = text;
+        el.title = `Sent ${traffic.packetsSent} packets (${traffic.bytesSent} bytes)\n` +
+            `Received ${traffic.packetsReceived} packets (${traffic.bytesReceived} bytes)\n` +
+            `Dropped (queue full): ${traffic.dropped}\n` +
+            `Malformed: ${traffic.malformed}\n` +
+            `Write errors: ${traffic.writeErrors}\n` +
+            `Peers: ${traffic.peers}`;
+    }
+
+    updateNowPlaying(nowPlaying) {
+        const item = document.getElementById('now-playing-item');
+
+        if (!nowPlaying) {
+            item.style.display = 'none';
+            return;
+        }
+
+        let title = nowPlaying.title || 'Unknown';
+        if (nowPlaying.artist) {
+            title = `${nowPlaying.artist} - ${title}`;
+        }
+        if (title !== this.nowPlayingTitle) {
+            this.nowPlayingTitle = title;
+            this.addLog(`Now playing: ${title}`, 'info');
+        }
+
+        const details = [];
+        if (nowPlaying.album) {
+            details.push(nowPlaying.album);
+        }
+        if (nowPlaying.duration > 0) {
+            details.push(`${this.formatTime(nowPlaying.position)} / ${this.formatTime(nowPlaying.duration)}`);
+        }
+        if (nowPlaying.stationText) {
+            details.push(nowPlaying.stationText);
+        }
+
+        document.getElementById('now-playing').textContent = title;
+        document.getElementById('now-playing-detail').textContent = details.join(' | ');
+        item.style.display = 'flex';
+    }
+
+    updateOffAir(offAir) {
+        const item = document.getElementById('off-air-item');
+
+        if (!offAir) {
+            if (this.offAirReason) {
+                this.offAirReason = null;
+                this.addLog('Station back on air', 'success');
+            }
+            item.style.display = 'none';
+            return;
+        }
+
+        let text = `Station off air (${offAir.reason})`;
+        if (offAir.backAt) {
+            const backAt = new Date(offAir.backAt * 1000);
+            text += `, back at ${backAt.toLocaleTimeString([], { hour: '2-digit', minute: '2-digit' })}`;
+        }
+        if (offAir.reason !== this.offAirReason) {
+            this.offAirReason = offAir.reason;
+            this.addLog(text, 'warning');
+        }
+
+        document.getElementById('off-air').textContent = text;
+        item.style.display = 'flex';
+    }
+
+    updateReception(listeners) {
+        const rows = document.getElementById('reception-rows');
+        rows.innerHTML = '';
+
+        if (listeners.length === 0) {
+            const row = rows.insertRow();
+            const cell = row.insertCell();
+            cell.colSpan = 6;
+            cell.className = 'reception-empty';
+            cell.textContent = 'No listener reports yet';
+            return;
+        }
+
+        for (const l of listeners) {
+            const row = rows.insertRow();
+            row.title = `${l.address} | received ${l.received}, lost ${l.lost} | last report ${l.lastReport}s ago`;
+            if (l.quality < 80) {
+                row.className = 'poor';
+            }
+
+            const values = [
+                l.callsign || l.address,
+                `${l.quality}%`,
+                `${l.lossPercent.toFixed(1)}%`,
+                `${l.jitterMs.toFixed(1)} ms`,
+                `${l.buffer}%`,
+                l.errors
+            ];
+            for (const value of values) {
+                row.insertCell().textContent = value;
+            }
+        }
+    }
+
+    formatTime(seconds) {
+        const m = Math.floor(seconds / 60);
+        const s = String(seconds % 60).padStart(2, '0');
+        return `${m}:${s}`;
+    }
+
     updateNetworkStatus(connected) {
         const indicator = document.getElementById('network-status');
         const text = document.getElementById('network-text');
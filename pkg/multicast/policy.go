@@ -0,0 +1,321 @@
+package multicast
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/meshradio/meshradio/pkg/yggdrasil"
+)
+
+// Admission errors returned by Subscribe
+var (
+	ErrGroupFull = errors.New("group is full")
+	ErrDenied    = errors.New("listener is not allowed in this group")
+	ErrBanned    = errors.New("listener is banned from this group")
+)
+
+// AllGroups is the group name for policies and bans that apply to every group
+// A group's own policy replaces the AllGroups policy; bans of both apply.
+const AllGroups = "*"
+
+// Policy decides which listeners may subscribe to a group
+type Policy struct {
+	MaxSubscribers int    // Optional: reject new subscribers beyond this count (0 = unlimited)
+	Allow          []Rule // Optional: only listeners matching one of these may subscribe (empty = everyone)
+	Deny           []Rule // Optional: listeners matching one of these may not subscribe
+}
+
+// Rule matches listeners by callsign, address prefix or public key
+// Exactly one field is set; see ParseRule. Callsign rules only match a callsign
+// the listener signed for with a trusted key, never the one it merely claims.
+type Rule struct {
+	Callsign  string            // Case-insensitive, matched against Subscriber.VerifiedCallsign
+	Prefix    *net.IPNet        // Listener address within this prefix
+	PublicKey ed25519.PublicKey // Listener address is the Yggdrasil address of this key
+}
+
+// Ban keeps matching listeners out of a group until it expires
+type Ban struct {
+	Rule   Rule
+	Until  time.Time // Zero = until lifted
+	Reason string
+}
+
+// ParseRule parses a rule: a callsign ("W1AW"), an address or prefix
+// ("200:1234::5678", "200:1234::/64") or a public key ("key:HEX")
+func ParseRule(s string) (Rule, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Rule{}, fmt.Errorf("empty rule")
+	}
+
+	if hexKey, ok := strings.CutPrefix(s, "key:"); ok {
+		raw, err := hex.DecodeString(hexKey)
+		if err != nil || len(raw) != ed25519.PublicKeySize {
+			return Rule{}, fmt.Errorf("invalid public key %q", hexKey)
+		}
+		return Rule{PublicKey: ed25519.PublicKey(raw)}, nil
+	}
+
+	if strings.Contains(s, "/") {
+		_, prefix, err := net.ParseCIDR(s)
+		if err != nil {
+			return Rule{}, fmt.Errorf("invalid prefix %q: %w", s, err)
+		}
+		return Rule{Prefix: prefix}, nil
+	}
+
+	if ip := net.ParseIP(s); ip != nil {
+		return AddressRule(ip), nil
+	}
+	if strings.Contains(s, ":") {
+		return Rule{}, fmt.Errorf("invalid address %q", s)
+	}
+
+	return Rule{Callsign: s}, nil
+}
+
+// ParseRules parses a comma-separated list of rules ("" = none)
+func ParseRules(s string) ([]Rule, error) {
+	var rules []Rule
+	for _, part := range strings.Split(s, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		rule, err := ParseRule(part)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// AddressRule returns a rule matching a single listener address
+func AddressRule(ip net.IP) Rule {
+	return Rule{Prefix: &net.IPNet{IP: ip.To16(), Mask: net.CIDRMask(128, 128)}}
+}
+
+// Matches reports whether a subscriber matches the rule
+func (r Rule) Matches(sub *Subscriber) bool {
+	switch {
+	case r.Callsign != "":
+		return sub.VerifiedCallsign != "" && strings.EqualFold(r.Callsign, sub.VerifiedCallsign)
+	case r.Prefix != nil:
+		return r.Prefix.Contains(sub.IPv6)
+	case r.PublicKey != nil:
+		return yggdrasil.AddressForKey(r.PublicKey).Equal(sub.IPv6)
+	}
+	return false
+}
+
+// String returns the rule in ParseRule form
+func (r Rule) String() string {
+	switch {
+	case r.Callsign != "":
+		return r.Callsign
+	case r.Prefix != nil:
+		if ones, bits := r.Prefix.Mask.Size(); ones == bits {
+			return r.Prefix.IP.String()
+		}
+		return r.Prefix.String()
+	case r.PublicKey != nil:
+		return "key:" + hex.EncodeToString(r.PublicKey)
+	}
+	return "(empty rule)"
+}
+
+// sameRule reports whether two rules match the same listeners
+func sameRule(a, b Rule) bool {
+	return a.String() == b.String()
+}
+
+// SetPolicy sets the admission policy of a group (AllGroups = default for all)
+// Current subscribers stay; the policy applies to new subscriptions.
+func (sm *SubscriptionManager) SetPolicy(group string, policy Policy) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.policies[group] = policy
+}
+
+// SetMaxSubscribers caps a group's subscribers apart from its policy (0 = no cap)
+// Unlike SetPolicy it leaves the allow and deny rules alone, so a group with a
+// cap still follows later changes to the AllGroups policy. If the policy has a
+// cap too, the lower one applies.
+func (sm *SubscriptionManager) SetMaxSubscribers(group string, max int) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if max > 0 {
+		sm.caps[group] = max
+	} else {
+		delete(sm.caps, group)
+	}
+}
+
+// GetPolicy returns the policy that applies to a group
+func (sm *SubscriptionManager) GetPolicy(group string) Policy {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	return sm.policyFor(group)
+}
+
+// Removal is a subscriber taken out of a group by a ban
+type Removal struct {
+	Group      string
+	Subscriber *Subscriber
+}
+
+// Ban keeps listeners matching a rule out of a group (AllGroups = every group)
+// until the given time (zero = until Unban). Matching subscribers are removed
+// and returned, so the broadcasters can tell them.
+func (sm *SubscriptionManager) Ban(group string, rule Rule, until time.Time, reason string) []Removal {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	bans := sm.bans[group]
+	for i := range bans {
+		if sameRule(bans[i].Rule, rule) {
+			bans = append(bans[:i], bans[i+1:]...)
+			break
+		}
+	}
+	sm.bans[group] = append(bans, Ban{Rule: rule, Until: until, Reason: reason})
+
+	var removed []Removal
+	for name, g := range sm.groups {
+		if group != AllGroups && name != group {
+			continue
+		}
+		for key, sub := range g.Subscribers {
+			if rule.Matches(sub) {
+				delete(g.Subscribers, key)
+				removed = append(removed, Removal{Group: name, Subscriber: sub})
+			}
+		}
+	}
+	return removed
+}
+
+// Unban lifts a ban; returns false if there was none for the rule
+func (sm *SubscriptionManager) Unban(group string, rule Rule) bool {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	bans := sm.bans[group]
+	for i := range bans {
+		if sameRule(bans[i].Rule, rule) {
+			sm.bans[group] = append(bans[:i], bans[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// GetBans returns the active bans of a group (AllGroups = bans for every group)
+func (sm *SubscriptionManager) GetBans(group string) []Ban {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.expireBans(time.Now())
+	return append([]Ban(nil), sm.bans[group]...)
+}
+
+// Kick removes a subscriber from a group
+// It may subscribe again unless a ban or the policy keeps it out.
+func (sm *SubscriptionManager) Kick(group string, ipv6 net.IP, port int) (*Subscriber, error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	g, exists := sm.groups[group]
+	if !exists {
+		return nil, fmt.Errorf("group not found: %s", group)
+	}
+
+	sub := g.GetSubscriber(ipv6, port)
+	if sub == nil {
+		return nil, fmt.Errorf("subscriber not found: %s:%d", ipv6, port)
+	}
+	g.RemoveSubscriber(ipv6, port)
+	return sub, nil
+}
+
+// admit checks a subscription against bans and the group policy.
+// Re-subscribes of current subscribers are not counted against the cap.
+// Caller must hold sm.mu for writing.
+func (sm *SubscriptionManager) admit(group string, sub *Subscriber) error {
+	now := time.Now()
+	sm.expireBans(now)
+
+	for _, name := range []string{group, AllGroups} {
+		for _, ban := range sm.bans[name] {
+			if ban.Rule.Matches(sub) {
+				return ErrBanned
+			}
+		}
+	}
+
+	policy := sm.policyFor(group)
+	for _, rule := range policy.Deny {
+		if rule.Matches(sub) {
+			return ErrDenied
+		}
+	}
+	if len(policy.Allow) > 0 {
+		allowed := false
+		for _, rule := range policy.Allow {
+			if rule.Matches(sub) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return ErrDenied
+		}
+	}
+
+	limit := policy.MaxSubscribers
+	if c := sm.caps[group]; c > 0 && (limit == 0 || c < limit) {
+		limit = c
+	}
+	if limit > 0 {
+		if g, exists := sm.groups[group]; exists && g.GetSubscriber(sub.IPv6, sub.Port) == nil &&
+			g.SubscriberCount() >= limit {
+			return ErrGroupFull
+		}
+	}
+
+	return nil
+}
+
+// policyFor returns a group's policy, or the AllGroups policy. Caller must hold sm.mu.
+func (sm *SubscriptionManager) policyFor(group string) Policy {
+	if policy, ok := sm.policies[group]; ok {
+		return policy
+	}
+	return sm.policies[AllGroups]
+}
+
+// expireBans drops bans that ran out. Caller must hold sm.mu for writing.
+func (sm *SubscriptionManager) expireBans(now time.Time) {
+	for group, bans := range sm.bans {
+		kept := bans[:0]
+		for _, ban := range bans {
+			if ban.Until.IsZero() || now.Before(ban.Until) {
+				kept = append(kept, ban)
+			}
+		}
+		if len(kept) == 0 {
+			delete(sm.bans, group)
+		} else {
+			sm.bans[group] = kept
+		}
+	}
+}
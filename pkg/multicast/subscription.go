@@ -9,22 +9,33 @@ import (
 
 // SubscriptionManager manages multicast group subscriptions
 type SubscriptionManager struct {
-	groups map[string]*Group // Key: group name
-	mu     sync.RWMutex
+	groups   map[string]*Group  // Key: group name
+	policies map[string]Policy  // Key: group name or AllGroups
+	caps     map[string]int     // Key: group name; listener caps set apart from the policy
+	bans     map[string][]Ban   // Key: group name or AllGroups
+	mu       sync.RWMutex
 }
 
 // NewSubscriptionManager creates a new subscription manager
 func NewSubscriptionManager() *SubscriptionManager {
 	return &SubscriptionManager{
-		groups: make(map[string]*Group),
+		groups:   make(map[string]*Group),
+		policies: make(map[string]Policy),
+		caps:     make(map[string]int),
+		bans:     make(map[string][]Ban),
 	}
 }
 
 // Subscribe adds a subscriber to a group
+// Returns ErrBanned, ErrDenied or ErrGroupFull if the admission policy refuses it.
 func (sm *SubscriptionManager) Subscribe(req SubscribeRequest) error {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
+	if err := sm.admit(req.Group, req.Subscriber); err != nil {
+		return err
+	}
+
 	// Create group if it doesn't exist
 	if _, exists := sm.groups[req.Group]; !exists {
 		sm.groups[req.Group] = NewGroup(req.Group)
@@ -80,7 +91,27 @@ func (sm *SubscriptionManager) Heartbeat(group string, ipv6 net.IP, port int) er
 	return nil
 }
 
-// GetSubscribers returns all subscribers for a group
+// SetNative records whether a subscriber receives the native IPv6 multicast stream
+func (sm *SubscriptionManager) SetNative(group string, ipv6 net.IP, port int, native bool) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	g, exists := sm.groups[group]
+	if !exists {
+		return fmt.Errorf("group not found: %s", group)
+	}
+
+	sub := g.GetSubscriber(ipv6, port)
+	if sub == nil {
+		return fmt.Errorf("subscriber not found: %s:%d", ipv6, port)
+	}
+
+	sub.Native = native
+	return nil
+}
+
+// GetSubscribers returns copies of all subscribers for a group
+// Copies, because heartbeats update the stored ones while callers fan out.
 func (sm *SubscriptionManager) GetSubscribers(group string) []*Subscriber {
 	sm.mu.RLock()
 	defer sm.mu.RUnlock()
@@ -90,10 +121,10 @@ func (sm *SubscriptionManager) GetSubscribers(group string) []*Subscriber {
 		return nil
 	}
 
-	return g.GetSubscribers()
+	return copySubscribers(g.GetSubscribers())
 }
 
-// GetSubscribersForSource returns subscribers that want packets from this source
+// GetSubscribersForSource returns copies of the subscribers that want packets from this source
 func (sm *SubscriptionManager) GetSubscribersForSource(group string, source net.IP) []*Subscriber {
 	sm.mu.RLock()
 	defer sm.mu.RUnlock()
@@ -103,7 +134,17 @@ func (sm *SubscriptionManager) GetSubscribersForSource(group string, source net.
 		return nil
 	}
 
-	return g.GetSubscribersForSource(source)
+	return copySubscribers(g.GetSubscribersForSource(source))
+}
+
+// copySubscribers snapshots subscribers. Caller must hold sm.mu.
+func copySubscribers(subs []*Subscriber) []*Subscriber {
+	copies := make([]*Subscriber, len(subs))
+	for i, sub := range subs {
+		c := *sub
+		copies[i] = &c
+	}
+	return copies
 }
 
 // RegisterBroadcaster registers a broadcaster for a group
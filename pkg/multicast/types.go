@@ -13,6 +13,9 @@ type Subscriber struct {
 	Callsign  string    // Station callsign
 	LastSeen  time.Time // Last heartbeat received
 	SSMSource net.IP    // nil = regular multicast, non-nil = SSM (only receive from this source)
+	Native    bool      // Receives native IPv6 multicast, skipped by unicast fan-out
+
+	VerifiedCallsign string // Callsign proven by a trusted signature on the SUBSCRIBE ("" = unverified)
 }
 
 // Broadcaster represents a broadcaster in a multicast group
@@ -0,0 +1,115 @@
+package network
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net"
+
+	"golang.org/x/net/ipv6"
+)
+
+// IPv6 multicast scopes (RFC 4291)
+const (
+	ScopeLinkLocal    uint8 = 0x2
+	ScopeRealmLocal   uint8 = 0x3
+	ScopeAdminLocal   uint8 = 0x4
+	ScopeSiteLocal    uint8 = 0x5
+	ScopeOrganization uint8 = 0x8
+	ScopeGlobal       uint8 = 0xe
+)
+
+// Native multicast defaults
+const (
+	DefaultMulticastPort     = 8780
+	DefaultMulticastScope    = ScopeSiteLocal
+	DefaultMulticastHopLimit = 8
+)
+
+// MulticastConfig enables native IPv6 multicast for a broadcaster or listener
+type MulticastConfig struct {
+	Interface *net.Interface // Optional: interface to send on / join on (default: system choice)
+	Scope     uint8          // Optional: group address scope, broadcaster only (default: site-local)
+	Port      int            // Optional: UDP port of the group stream, broadcaster only (default: 8780)
+	HopLimit  int            // Optional: multicast hop limit, broadcaster only (default: 8)
+}
+
+// MulticastSender is implemented by transports that can send to IPv6 multicast groups
+type MulticastSender interface {
+	SetMulticastOptions(ifi *net.Interface, hopLimit int) error
+}
+
+// GroupAddress maps a group name to a transient IPv6 multicast address (ff1X::/16)
+// Every node derives the same address from the name, so no allocation is needed.
+func GroupAddress(group string, scope uint8) net.IP {
+	h := fnv.New32a()
+	h.Write([]byte("meshradio:" + group))
+	sum := h.Sum32()
+
+	addr := make(net.IP, net.IPv6len)
+	addr[0] = 0xff
+	addr[1] = 0x10 | (scope & 0x0f) // T flag: not IANA-assigned
+	addr[12] = byte(sum >> 24)
+	addr[13] = byte(sum >> 16)
+	addr[14] = byte(sum >> 8)
+	addr[15] = byte(sum)
+	return addr
+}
+
+// SetMulticastOptions prepares the socket for sending to multicast groups
+func (t *UDPTransport) SetMulticastOptions(ifi *net.Interface, hopLimit int) error {
+	if ifi != nil {
+		if err := t.pconn.SetMulticastInterface(ifi); err != nil {
+			return fmt.Errorf("failed to set multicast interface: %w", err)
+		}
+	}
+	if err := t.pconn.SetMulticastHopLimit(hopLimit); err != nil {
+		return fmt.Errorf("failed to set multicast hop limit: %w", err)
+	}
+	// Listeners on the broadcasting host should hear the stream too
+	if err := t.pconn.SetMulticastLoopback(true); err != nil {
+		return fmt.Errorf("failed to enable multicast loopback: %w", err)
+	}
+	return nil
+}
+
+// ListenMulticast creates a transport that receives a native multicast group
+// With a source it joins source-specifically (SSM), otherwise any source (ASM).
+// The socket is bound to the group address, so several listeners on one host
+// can share the port; closing the transport leaves the group.
+func ListenMulticast(group net.IP, port int, ifi *net.Interface, source net.IP) (*UDPTransport, error) {
+	if !group.IsMulticast() {
+		return nil, fmt.Errorf("not a multicast address: %s", group)
+	}
+
+	addr := &net.UDPAddr{
+		IP:   group,
+		Port: port,
+	}
+	if group.IsLinkLocalMulticast() && ifi != nil {
+		addr.Zone = ifi.Name
+	}
+
+	conn, err := net.ListenUDP("udp6", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create multicast socket: %w", err)
+	}
+
+	pconn := ipv6.NewPacketConn(conn)
+	groupAddr := &net.UDPAddr{IP: group}
+	if source != nil {
+		err = pconn.JoinSourceSpecificGroup(ifi, groupAddr, &net.UDPAddr{IP: source})
+	} else {
+		err = pconn.JoinGroup(ifi, groupAddr)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to join multicast group %s: %w", group, err)
+	}
+
+	return &UDPTransport{
+		conn:      conn,
+		pconn:     pconn,
+		localAddr: addr,
+		packets:   NewPacketQueue(100),
+	}, nil
+}
@@ -0,0 +1,145 @@
+package network
+
+import (
+	"net"
+	"sync"
+
+	"github.com/meshradio/meshradio/pkg/emergency"
+	"github.com/meshradio/meshradio/pkg/protocol"
+)
+
+// ReservedQueueCapacity is the room each non-bulk class of a PacketQueue
+// keeps for itself, however much normal audio is backed up
+const ReservedQueueCapacity = 32
+
+// QueueClass orders packets in a PacketQueue (lower is served first)
+type QueueClass int
+
+const (
+	QueueEmergency QueueClass = iota // Emergency priority audio and emergency alerts
+	QueueControl                     // Subscription control, bounded on its own so a SUBSCRIBE flood only sheds itself
+	QueueElevated                    // High priority audio and other control traffic
+	QueueNormal                      // Normal priority audio, shed first
+	numQueueClasses
+)
+
+// ClassifyPacket returns the queue class of a packet
+// Only audio and emergency alerts can claim the emergency class: priority
+// bits on anything else (e.g. a forged SUBSCRIBE) buy nothing.
+func ClassifyPacket(packet *protocol.Packet) QueueClass {
+	priority := emergency.Priority(packet.GetPriority())
+
+	switch packet.Type {
+	case protocol.PacketTypeEmergency:
+		return QueueEmergency
+	case protocol.PacketTypeAudio:
+		switch {
+		case priority >= emergency.PriorityEmergency:
+			return QueueEmergency
+		case priority > emergency.PriorityNormal:
+			return QueueElevated
+		}
+		return QueueNormal
+	case protocol.PacketTypeSubscribe,
+		protocol.PacketTypeUnsubscribe,
+		protocol.PacketTypeSubscribeAck,
+		protocol.PacketTypeSubscribeChallenge:
+		return QueueControl
+	default:
+		return QueueElevated
+	}
+}
+
+// PacketQueue is a bounded, priority-aware packet queue
+// Every class has its own capacity, so a flood of normal audio can never
+// crowd out an emergency packet. Pop serves emergency packets first, then
+// subscription control, elevated packets and finally normal audio. A full class sheds its oldest packet:
+// for realtime audio the newest frame is the one worth keeping.
+type PacketQueue struct {
+	mu       sync.Mutex
+	nonEmpty *sync.Cond
+	classes  [numQueueClasses][]datagram
+	limits   [numQueueClasses]int
+	closed   bool
+}
+
+// NewPacketQueue creates a queue holding up to capacity normal audio packets,
+// plus ReservedQueueCapacity for each of the other classes
+func NewPacketQueue(capacity int) *PacketQueue {
+	q := &PacketQueue{}
+	q.nonEmpty = sync.NewCond(&q.mu)
+	q.limits[QueueEmergency] = ReservedQueueCapacity
+	q.limits[QueueControl] = ReservedQueueCapacity
+	q.limits[QueueElevated] = ReservedQueueCapacity
+	q.limits[QueueNormal] = capacity
+	return q
+}
+
+// Push queues a packet without blocking
+// Returns true if an older packet of the same class was shed to make room.
+func (q *PacketQueue) Push(packet *protocol.Packet, from *net.UDPAddr) bool {
+	class := ClassifyPacket(packet)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return false
+	}
+
+	shed := false
+	if len(q.classes[class]) >= q.limits[class] {
+		q.classes[class][0] = datagram{}
+		q.classes[class] = q.classes[class][1:]
+		shed = true
+	}
+	q.classes[class] = append(q.classes[class], datagram{packet: packet, from: from})
+
+	q.nonEmpty.Signal()
+	return shed
+}
+
+// Pop blocks for the most important queued packet
+// After Close it drains what is left, then returns false.
+func (q *PacketQueue) Pop() (*protocol.Packet, *net.UDPAddr, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for {
+		for class := range q.classes {
+			if len(q.classes[class]) == 0 {
+				continue
+			}
+			d := q.classes[class][0]
+			q.classes[class][0] = datagram{}
+			q.classes[class] = q.classes[class][1:]
+			return d.packet, d.from, true
+		}
+
+		if q.closed {
+			return nil, nil, false
+		}
+		q.nonEmpty.Wait()
+	}
+}
+
+// Len returns the number of queued packets
+func (q *PacketQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	n := 0
+	for _, c := range q.classes {
+		n += len(c)
+	}
+	return n
+}
+
+// Close wakes all waiting Pop calls; later pushes are ignored
+func (q *PacketQueue) Close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+
+	q.nonEmpty.Broadcast()
+}
@@ -0,0 +1,150 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/meshradio/meshradio/pkg/emergency"
+	"github.com/meshradio/meshradio/pkg/protocol"
+)
+
+// testPacket builds a packet of a type and priority, numbered by seq
+func testPacket(packetType uint8, priority emergency.Priority, seq uint32) *protocol.Packet {
+	p := protocol.NewPacket(packetType, [16]byte{}, "TEST", nil)
+	p.SetPriority(uint8(priority))
+	p.SequenceNum = seq
+	return p
+}
+
+func TestClassifyPacket(t *testing.T) {
+	tests := []struct {
+		name       string
+		packetType uint8
+		priority   emergency.Priority
+		want       QueueClass
+	}{
+		{"normal audio", protocol.PacketTypeAudio, emergency.PriorityNormal, QueueNormal},
+		{"high audio", protocol.PacketTypeAudio, emergency.PriorityHigh, QueueElevated},
+		{"emergency audio", protocol.PacketTypeAudio, emergency.PriorityEmergency, QueueEmergency},
+		{"critical audio", protocol.PacketTypeAudio, emergency.PriorityCritical, QueueEmergency},
+		{"emergency alert", protocol.PacketTypeEmergency, emergency.PriorityNormal, QueueEmergency},
+		{"subscribe", protocol.PacketTypeSubscribe, emergency.PriorityNormal, QueueControl},
+		{"subscribe claiming critical", protocol.PacketTypeSubscribe, emergency.PriorityCritical, QueueControl},
+		{"unsubscribe", protocol.PacketTypeUnsubscribe, emergency.PriorityNormal, QueueControl},
+		{"subscribe ack", protocol.PacketTypeSubscribeAck, emergency.PriorityNormal, QueueControl},
+		{"challenge", protocol.PacketTypeSubscribeChallenge, emergency.PriorityNormal, QueueControl},
+		{"heartbeat", protocol.PacketTypeHeartbeat, emergency.PriorityNormal, QueueElevated},
+		{"metadata", protocol.PacketTypeMetadata, emergency.PriorityNormal, QueueElevated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyPacket(testPacket(tt.packetType, tt.priority, 0)); got != tt.want {
+				t.Errorf("ClassifyPacket() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPacketQueueFlood(t *testing.T) {
+	const capacity = 64
+
+	tests := []struct {
+		name       string
+		floodType  uint8
+		floodPrio  emergency.Priority
+		floodLimit int // Capacity of the flooded class
+	}{
+		{"normal audio", protocol.PacketTypeAudio, emergency.PriorityNormal, capacity},
+		{"high audio", protocol.PacketTypeAudio, emergency.PriorityHigh, ReservedQueueCapacity},
+		{"subscribe", protocol.PacketTypeSubscribe, emergency.PriorityNormal, ReservedQueueCapacity},
+		{"forged critical subscribe", protocol.PacketTypeSubscribe, emergency.PriorityCritical, ReservedQueueCapacity},
+		{"heartbeat", protocol.PacketTypeHeartbeat, emergency.PriorityNormal, ReservedQueueCapacity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewPacketQueue(capacity)
+			defer q.Close()
+
+			// Emergency packets first, then a flood many times the class capacity
+			const emergencyCount = 4
+			for i := 0; i < emergencyCount; i++ {
+				q.Push(testPacket(protocol.PacketTypeAudio, emergency.PriorityCritical, uint32(i)), nil)
+			}
+			shed := 0
+			for i := 0; i < 10*tt.floodLimit; i++ {
+				if q.Push(testPacket(tt.floodType, tt.floodPrio, uint32(1000+i)), nil) {
+					shed++
+				}
+			}
+
+			if want := 9 * tt.floodLimit; shed != want {
+				t.Errorf("shed %d flood packets, want %d", shed, want)
+			}
+			if want := emergencyCount + tt.floodLimit; q.Len() != want {
+				t.Fatalf("Len() = %d, want %d", q.Len(), want)
+			}
+
+			// Every emergency packet survived and comes out first, in order
+			for i := 0; i < emergencyCount; i++ {
+				p, _, ok := q.Pop()
+				if !ok {
+					t.Fatalf("Pop() failed at emergency packet %d", i)
+				}
+				if ClassifyPacket(p) != QueueEmergency || p.SequenceNum != uint32(i) {
+					t.Fatalf("Pop() #%d = type %d seq %d, want emergency seq %d", i, p.Type, p.SequenceNum, i)
+				}
+			}
+
+			// The flood keeps its newest packets
+			p, _, _ := q.Pop()
+			if want := uint32(1000 + 9*tt.floodLimit); p.SequenceNum != want {
+				t.Errorf("oldest kept flood packet seq = %d, want %d", p.SequenceNum, want)
+			}
+		})
+	}
+}
+
+func TestPacketQueueEmergencyOvertakes(t *testing.T) {
+	q := NewPacketQueue(16)
+	defer q.Close()
+
+	// Fill every other class, then one late emergency packet
+	for i := 0; i < 16; i++ {
+		q.Push(testPacket(protocol.PacketTypeAudio, emergency.PriorityNormal, uint32(i)), nil)
+		q.Push(testPacket(protocol.PacketTypeSubscribe, emergency.PriorityNormal, uint32(i)), nil)
+		q.Push(testPacket(protocol.PacketTypeMetadata, emergency.PriorityNormal, uint32(i)), nil)
+	}
+	q.Push(testPacket(protocol.PacketTypeEmergency, emergency.PriorityCritical, 99), nil)
+
+	wantOrder := []QueueClass{QueueEmergency, QueueControl, QueueElevated, QueueNormal}
+	for _, want := range wantOrder {
+		p, _, ok := q.Pop()
+		if !ok {
+			t.Fatal("Pop() failed")
+		}
+		if got := ClassifyPacket(p); got != want {
+			t.Fatalf("Pop() class = %d, want %d", got, want)
+		}
+		// Drain the rest of this class
+		for i := 0; want != QueueEmergency && i < 15; i++ {
+			q.Pop()
+		}
+	}
+}
+
+func TestPacketQueueClose(t *testing.T) {
+	q := NewPacketQueue(4)
+	q.Push(testPacket(protocol.PacketTypeAudio, emergency.PriorityNormal, 1), nil)
+	q.Close()
+
+	if q.Push(testPacket(protocol.PacketTypeAudio, emergency.PriorityNormal, 2), nil) {
+		t.Error("Push() after Close shed a packet")
+	}
+	if _, _, ok := q.Pop(); !ok {
+		t.Error("Pop() after Close did not drain the queued packet")
+	}
+	if _, _, ok := q.Pop(); ok {
+		t.Error("Pop() on a closed, empty queue returned a packet")
+	}
+}
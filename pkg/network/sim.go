@@ -0,0 +1,412 @@
+package network
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/meshradio/meshradio/pkg/protocol"
+)
+
+// simFirstEphemeralPort is where port 0 allocations start
+const simFirstEphemeralPort = 49152
+
+// defaultSimQueueLimit caps queueing delay on bandwidth-limited links
+const defaultSimQueueLimit = 1 * time.Second
+
+// LinkConfig describes the impairments of a one-way simulated link
+type LinkConfig struct {
+	Delay        time.Duration // Base one-way delay
+	Jitter       time.Duration // Random extra delay, uniform in [0, Jitter)
+	Loss         float64       // Probability a packet is dropped (0-1)
+	Duplicate    float64       // Probability a packet is delivered twice (0-1)
+	Reorder      float64       // Probability a packet is held back so later ones overtake it (0-1)
+	ReorderDelay time.Duration // Optional: hold-back for reordered packets (default: Delay + Jitter + 10ms)
+	Bandwidth    int           // Bits per second (0 = unlimited)
+	QueueLimit   time.Duration // Optional: queueing delay before tail drop on limited links (default: 1s)
+}
+
+// SimNetwork is an in-memory network for deterministic tests
+// Endpoints are addressed like UDP sockets; links between hosts can be
+// given delay, jitter, loss, duplication, reordering and bandwidth caps.
+// All random decisions come from one seeded source, in send order.
+type SimNetwork struct {
+	mu          sync.Mutex
+	rng         *rand.Rand
+	endpoints   map[string]*SimTransport // key: "[ipv6]:port"
+	links       map[simLinkKey]LinkConfig
+	busyUntil   map[simLinkKey]time.Time // Bandwidth queue state per link
+	defaultLink LinkConfig
+	nextPort    int
+
+	// Scheduled deliveries, in arrival order
+	pending simDeliveries
+	sent    uint64 // Send order, breaks ties between equal arrival times
+	timer   *time.Timer
+	timerAt time.Time
+	deliver sync.Mutex // Serializes delivery, so a late timer cannot overtake
+}
+
+// simLinkKey identifies a one-way link between two hosts
+type simLinkKey struct {
+	from string
+	to   string
+}
+
+// NewSimNetwork creates an empty simulated network
+// The seed makes loss, jitter and the other impairments reproducible.
+func NewSimNetwork(seed int64) *SimNetwork {
+	return &SimNetwork{
+		rng:       rand.New(rand.NewSource(seed)),
+		endpoints: make(map[string]*SimTransport),
+		links:     make(map[simLinkKey]LinkConfig),
+		busyUntil: make(map[simLinkKey]time.Time),
+		nextPort:  simFirstEphemeralPort,
+	}
+}
+
+// SetDefaultLink sets the impairments for host pairs without their own link
+func (n *SimNetwork) SetDefaultLink(cfg LinkConfig) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.defaultLink = cfg
+}
+
+// SetLink sets the impairments from one host to another (one direction only)
+func (n *SimNetwork) SetLink(from, to net.IP, cfg LinkConfig) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.links[simLinkKey{from: from.String(), to: to.String()}] = cfg
+}
+
+// Endpoint creates a transport bound to ipv6:port on the simulated network
+// Port 0 picks a free ephemeral port, like the kernel does.
+func (n *SimNetwork) Endpoint(ipv6 net.IP, port int) (*SimTransport, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if port == 0 {
+		for n.endpoints[simAddrKey(ipv6, n.nextPort)] != nil {
+			n.nextPort++
+		}
+		port = n.nextPort
+		n.nextPort++
+	}
+
+	key := simAddrKey(ipv6, port)
+	if n.endpoints[key] != nil {
+		return nil, fmt.Errorf("address [%s]:%d already in use", ipv6, port)
+	}
+
+	t := &SimTransport{
+		network: n,
+		addr:    &net.UDPAddr{IP: ipv6, Port: port},
+		packets: NewPacketQueue(100),
+	}
+	n.endpoints[key] = t
+	return t, nil
+}
+
+// route applies link impairments and schedules delivery of one datagram
+// Like UDP, packets to unbound addresses vanish without an error.
+func (n *SimNetwork) route(from *net.UDPAddr, to *net.UDPAddr, data []byte) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	dst := n.endpoints[simAddrKey(to.IP, to.Port)]
+	if dst == nil {
+		return
+	}
+
+	key := simLinkKey{from: from.IP.String(), to: to.IP.String()}
+	cfg, ok := n.links[key]
+	if !ok {
+		cfg = n.defaultLink
+	}
+
+	if cfg.Loss > 0 && n.rng.Float64() < cfg.Loss {
+		return
+	}
+
+	delay := cfg.Delay
+	if cfg.Jitter > 0 {
+		delay += time.Duration(n.rng.Int63n(int64(cfg.Jitter)))
+	}
+
+	if cfg.Reorder > 0 && n.rng.Float64() < cfg.Reorder {
+		hold := cfg.ReorderDelay
+		if hold <= 0 {
+			hold = cfg.Delay + cfg.Jitter + 10*time.Millisecond
+		}
+		delay += hold
+	}
+
+	// Bandwidth cap: packets serialize one after another behind the link
+	if cfg.Bandwidth > 0 {
+		queueLimit := cfg.QueueLimit
+		if queueLimit <= 0 {
+			queueLimit = defaultSimQueueLimit
+		}
+
+		now := time.Now()
+		start := now
+		if busy := n.busyUntil[key]; busy.After(now) {
+			start = busy
+		}
+		if start.Sub(now) > queueLimit {
+			return // Tail drop
+		}
+
+		txTime := time.Duration(int64(len(data)) * 8 * int64(time.Second) / int64(cfg.Bandwidth))
+		done := start.Add(txTime)
+		n.busyUntil[key] = done
+		delay += done.Sub(now)
+	}
+
+	copies := 1
+	if cfg.Duplicate > 0 && n.rng.Float64() < cfg.Duplicate {
+		copies = 2
+	}
+
+	sender := &net.UDPAddr{IP: from.IP, Port: from.Port}
+	for i := 0; i < copies; i++ {
+		n.schedule(time.Now().Add(delay), dst, data, sender)
+	}
+}
+
+// simDelivery is a datagram on its way to an endpoint
+type simDelivery struct {
+	at   time.Time
+	seq  uint64
+	dst  *SimTransport
+	data []byte
+	from *net.UDPAddr
+}
+
+// simDeliveries is a min-heap of deliveries by arrival time, then send order
+type simDeliveries []*simDelivery
+
+func (d simDeliveries) Len() int { return len(d) }
+func (d simDeliveries) Less(i, j int) bool {
+	if d[i].at.Equal(d[j].at) {
+		return d[i].seq < d[j].seq
+	}
+	return d[i].at.Before(d[j].at)
+}
+func (d simDeliveries) Swap(i, j int) { d[i], d[j] = d[j], d[i] }
+func (d *simDeliveries) Push(x any)   { *d = append(*d, x.(*simDelivery)) }
+func (d *simDeliveries) Pop() any {
+	old := *d
+	last := old[len(old)-1]
+	*d = old[:len(old)-1]
+	return last
+}
+
+// schedule queues a delivery; caller holds n.mu
+// One timer serves all deliveries, so packets due at the same time arrive in
+// the order they were sent, like on a real link without jitter.
+func (n *SimNetwork) schedule(at time.Time, dst *SimTransport, data []byte, from *net.UDPAddr) {
+	n.sent++
+	heap.Push(&n.pending, &simDelivery{at: at, seq: n.sent, dst: dst, data: data, from: from})
+	n.armTimer()
+}
+
+// armTimer points the timer at the earliest pending delivery; caller holds n.mu
+func (n *SimNetwork) armTimer() {
+	if len(n.pending) == 0 {
+		return
+	}
+	next := n.pending[0].at
+	if n.timer != nil && !n.timerAt.After(next) {
+		return // Already due earlier, it re-arms after delivering
+	}
+	if n.timer != nil {
+		n.timer.Stop()
+	}
+	n.timerAt = next
+	n.timer = time.AfterFunc(time.Until(next), n.deliverDue)
+}
+
+// deliverDue hands all deliveries that are due to their endpoints, in order
+func (n *SimNetwork) deliverDue() {
+	n.deliver.Lock()
+	defer n.deliver.Unlock()
+
+	n.mu.Lock()
+	var due []*simDelivery
+	now := time.Now()
+	for len(n.pending) > 0 && !n.pending[0].at.After(now) {
+		due = append(due, heap.Pop(&n.pending).(*simDelivery))
+	}
+	n.timer = nil
+	n.armTimer()
+	n.mu.Unlock()
+
+	for _, d := range due {
+		d.dst.deliver(d.data, d.from)
+	}
+}
+
+// unbind removes a stopped endpoint so its address can be reused
+func (n *SimNetwork) unbind(t *SimTransport) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	key := simAddrKey(t.addr.IP, t.addr.Port)
+	if n.endpoints[key] == t {
+		delete(n.endpoints, key)
+	}
+}
+
+// simAddrKey builds the endpoint map key
+func simAddrKey(ipv6 net.IP, port int) string {
+	return net.JoinHostPort(ipv6.String(), strconv.Itoa(port))
+}
+
+// SimTransport is an endpoint on a SimNetwork
+type SimTransport struct {
+	network *SimNetwork
+	addr    *net.UDPAddr
+	running bool
+	closed  bool
+	mu      sync.Mutex
+
+	counters transportCounters
+
+	// Received packets, served by priority
+	packets *PacketQueue
+}
+
+// Start begins accepting packets
+func (t *SimTransport) Start() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.running {
+		return fmt.Errorf("transport already running")
+	}
+	if t.closed {
+		return fmt.Errorf("transport closed")
+	}
+	t.running = true
+	return nil
+}
+
+// Stop unbinds the endpoint; packets in flight to it are dropped
+func (t *SimTransport) Stop() error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return nil
+	}
+	t.running = false
+	t.closed = true
+	t.packets.Close()
+	t.mu.Unlock()
+
+	t.network.unbind(t)
+	return nil
+}
+
+// Send sends a packet through the simulated network
+// The packet is marshaled and parsed again on delivery, like on a real wire.
+func (t *SimTransport) Send(packet *protocol.Packet, targetIPv6 net.IP, port int) error {
+	data, err := packet.Marshal()
+	if err != nil {
+		return fmt.Errorf("failed to marshal packet: %w", err)
+	}
+
+	addr := &net.UDPAddr{IP: targetIPv6, Port: port}
+
+	t.mu.Lock()
+	closed := t.closed
+	t.mu.Unlock()
+	if closed {
+		t.counters.recordWriteError(addr)
+		return fmt.Errorf("failed to send packet: transport closed")
+	}
+
+	t.network.route(t.addr, addr, data)
+	t.counters.recordSent(packet.Type, len(data), addr)
+	return nil
+}
+
+// SendBatch sends one packet to many endpoints, marshaling it once
+func (t *SimTransport) SendBatch(packet *protocol.Packet, targets []*net.UDPAddr) ([]SendError, error) {
+	data, err := packet.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal packet: %w", err)
+	}
+
+	t.mu.Lock()
+	closed := t.closed
+	t.mu.Unlock()
+	if closed {
+		failed := make([]SendError, len(targets))
+		for i, addr := range targets {
+			t.counters.recordWriteError(addr)
+			failed[i] = SendError{Addr: addr, Err: fmt.Errorf("failed to send packet: transport closed")}
+		}
+		return failed, nil
+	}
+
+	for _, addr := range targets {
+		t.network.route(t.addr, addr, data)
+		t.counters.recordSent(packet.Type, len(data), addr)
+	}
+	return nil, nil
+}
+
+// Receive returns the next delivered packet and the endpoint it was sent from
+func (t *SimTransport) Receive() (*protocol.Packet, *net.UDPAddr, error) {
+	packet, from, ok := t.packets.Pop()
+	if !ok {
+		return nil, nil, fmt.Errorf("transport closed")
+	}
+	return packet, from, nil
+}
+
+// Stats returns a snapshot of the traffic and drop counters
+func (t *SimTransport) Stats() TransportStats {
+	return t.counters.snapshot()
+}
+
+// LocalAddr returns the simulated address of this endpoint
+func (t *SimTransport) LocalAddr() *net.UDPAddr {
+	return t.addr
+}
+
+// deliver parses a datagram and queues it for Receive
+func (t *SimTransport) deliver(data []byte, from *net.UDPAddr) {
+	packet, err := protocol.Unmarshal(data)
+	if err != nil {
+		t.counters.recordMalformed()
+		if errors.Is(err, protocol.ErrVersionMismatch) {
+			fmt.Printf("Dropping packet with unsupported protocol version %d\n", data[0])
+		} else {
+			fmt.Printf("Error unmarshaling packet: %v\n", err)
+		}
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return
+	}
+
+	t.counters.recordReceived(packet.Type, len(data), from)
+
+	// A full class sheds its oldest packet
+	if t.packets.Push(packet, from) {
+		t.counters.recordDroppedFull()
+	}
+}
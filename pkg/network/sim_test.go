@@ -0,0 +1,299 @@
+package network
+
+import (
+	"math/rand"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/meshradio/meshradio/pkg/protocol"
+)
+
+var (
+	simTestSender   = net.ParseIP("fd00::1")
+	simTestReceiver = net.ParseIP("fd00::2")
+)
+
+// simLink is a sender and a receiver joined by one impaired link
+type simLink struct {
+	net *SimNetwork
+	tx  *SimTransport
+	rx  *SimTransport
+}
+
+// newSimLink sets up a sender and a receiver with the given link between them
+// The receive queue is large enough to hold every packet a test sends.
+func newSimLink(t *testing.T, seed int64, cfg LinkConfig) *simLink {
+	t.Helper()
+
+	n := NewSimNetwork(seed)
+	n.SetLink(simTestSender, simTestReceiver, cfg)
+
+	tx, err := n.Endpoint(simTestSender, 1000)
+	if err != nil {
+		t.Fatalf("sender endpoint: %v", err)
+	}
+	rx, err := n.Endpoint(simTestReceiver, 2000)
+	if err != nil {
+		t.Fatalf("receiver endpoint: %v", err)
+	}
+	rx.packets = NewPacketQueue(1000)
+
+	for _, tr := range []*SimTransport{tx, rx} {
+		if err := tr.Start(); err != nil {
+			t.Fatalf("Start: %v", err)
+		}
+		t.Cleanup(func() { tr.Stop() })
+	}
+
+	return &simLink{net: n, tx: tx, rx: rx}
+}
+
+// send sends count audio packets back to back, numbered from first
+func (l *simLink) send(t *testing.T, first, count int) {
+	t.Helper()
+	for i := first; i < first+count; i++ {
+		p := protocol.NewPacket(protocol.PacketTypeAudio, [16]byte{}, "TEST", make([]byte, 100))
+		p.SequenceNum = uint32(i)
+		if err := l.tx.Send(p, simTestReceiver, 2000); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+}
+
+// settle waits until every packet in flight has been delivered, then returns
+// the sequence numbers received, in arrival order
+func (l *simLink) settle(t *testing.T) []uint32 {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		l.net.mu.Lock()
+		idle := len(l.net.pending) == 0
+		l.net.mu.Unlock()
+		if idle {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("packets still in flight after 5s")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// A delivery taken off the heap finishes before the lock is free
+	l.net.deliver.Lock()
+	l.net.deliver.Unlock()
+
+	var got []uint32
+	for l.rx.packets.Len() > 0 {
+		p, _, _ := l.rx.packets.Pop()
+		got = append(got, p.SequenceNum)
+	}
+	return got
+}
+
+// sequence returns first, first+1, ... count numbers
+func sequence(first, count int) []uint32 {
+	seqs := make([]uint32, count)
+	for i := range seqs {
+		seqs[i] = uint32(first + i)
+	}
+	return seqs
+}
+
+// runLink sends count packets over a fresh link and returns what arrived
+func runLink(t *testing.T, seed int64, cfg LinkConfig, count int) []uint32 {
+	t.Helper()
+	l := newSimLink(t, seed, cfg)
+	l.send(t, 0, count)
+	return l.settle(t)
+}
+
+func TestSimLinkDelay(t *testing.T) {
+	const delay = 30 * time.Millisecond
+
+	l := newSimLink(t, 1, LinkConfig{Delay: delay})
+	start := time.Now()
+	l.send(t, 0, 50)
+
+	if n := l.rx.packets.Len(); n != 0 {
+		t.Fatalf("%d packets arrived before the link delay", n)
+	}
+
+	got := l.settle(t)
+	if elapsed := time.Since(start); elapsed < delay {
+		t.Errorf("link idle after %v, want at least %v", elapsed, delay)
+	}
+	if want := sequence(0, 50); !reflect.DeepEqual(got, want) {
+		t.Errorf("received %v, want %v", got, want)
+	}
+}
+
+func TestSimLinkLoss(t *testing.T) {
+	const (
+		seed = 7
+		sent = 500
+	)
+
+	for _, loss := range []float64{0, 0.1, 0.3, 1} {
+		// One draw per packet, in send order, from the seeded source
+		rng := rand.New(rand.NewSource(seed))
+		want := []uint32{}
+		for i := 0; i < sent; i++ {
+			if loss == 0 || rng.Float64() >= loss {
+				want = append(want, uint32(i))
+			}
+		}
+
+		got := runLink(t, seed, LinkConfig{Loss: loss}, sent)
+		if got == nil {
+			got = []uint32{}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("loss %.1f: received %d packets %v, want %d %v", loss, len(got), got, len(want), want)
+		}
+	}
+}
+
+func TestSimLinkJitter(t *testing.T) {
+	const (
+		delay  = 10 * time.Millisecond
+		jitter = 20 * time.Millisecond
+		sent   = 100
+	)
+
+	l := newSimLink(t, 3, LinkConfig{Delay: delay, Jitter: jitter})
+	start := time.Now()
+	l.send(t, 0, sent)
+	got := l.settle(t)
+
+	if elapsed := time.Since(start); elapsed < delay {
+		t.Errorf("link idle after %v, want at least %v", elapsed, delay)
+	}
+
+	// Every packet arrives once, but not in send order
+	seen := make(map[uint32]bool)
+	inversions := 0
+	for i, seq := range got {
+		if seen[seq] {
+			t.Fatalf("packet %d arrived twice", seq)
+		}
+		seen[seq] = true
+		if i > 0 && seq < got[i-1] {
+			inversions++
+		}
+	}
+	if len(got) != sent {
+		t.Fatalf("received %d of %d packets", len(got), sent)
+	}
+	if inversions == 0 {
+		t.Error("jitter reordered nothing in a back-to-back burst")
+	}
+}
+
+func TestSimLinkDuplicate(t *testing.T) {
+	const (
+		seed      = 11
+		sent      = 200
+		duplicate = 0.25
+	)
+
+	// Copies arrive back to back, right behind the original
+	rng := rand.New(rand.NewSource(seed))
+	var want []uint32
+	for i := 0; i < sent; i++ {
+		want = append(want, uint32(i))
+		if rng.Float64() < duplicate {
+			want = append(want, uint32(i))
+		}
+	}
+
+	if len(want) == sent {
+		t.Fatal("seed duplicates nothing, pick another")
+	}
+
+	if got := runLink(t, seed, LinkConfig{Duplicate: duplicate}, sent); !reflect.DeepEqual(got, want) {
+		t.Errorf("received %v, want %v", got, want)
+	}
+}
+
+func TestSimLinkReorder(t *testing.T) {
+	const (
+		seed    = 5
+		sent    = 100
+		reorder = 0.2
+	)
+
+	tests := []struct {
+		name string
+		cfg  LinkConfig
+	}{
+		{"reorder delay", LinkConfig{Reorder: reorder, ReorderDelay: 30 * time.Millisecond}},
+		{"default hold-back", LinkConfig{Delay: 5 * time.Millisecond, Reorder: reorder}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The burst is sent well within the hold-back, so every packet
+			// that was not held arrives first, in order, then the held ones
+			rng := rand.New(rand.NewSource(seed))
+			var want, held []uint32
+			for i := 0; i < sent; i++ {
+				if rng.Float64() < reorder {
+					held = append(held, uint32(i))
+				} else {
+					want = append(want, uint32(i))
+				}
+			}
+			if len(held) == 0 {
+				t.Fatal("seed holds nothing back, pick another")
+			}
+			want = append(want, held...)
+
+			if got := runLink(t, seed, tt.cfg, sent); !reflect.DeepEqual(got, want) {
+				t.Errorf("received %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSimLinkBandwidth(t *testing.T) {
+	wire, err := protocol.NewPacket(protocol.PacketTypeAudio, [16]byte{}, "TEST", make([]byte, 100)).Marshal()
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	// One packet takes 10ms on the wire
+	const txTime = 10 * time.Millisecond
+	bandwidth := len(wire) * 8 * int(time.Second/txTime)
+
+	tests := []struct {
+		name       string
+		sent       int
+		queueLimit time.Duration
+		want       int
+	}{
+		// Packet k waits k*10ms; those waiting over 55ms are tail dropped
+		{"tail drop", 20, 55 * time.Millisecond, 6},
+		{"within queue limit", 4, 55 * time.Millisecond, 4},
+		{"default queue limit", 20, 0, 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newSimLink(t, 1, LinkConfig{Bandwidth: bandwidth, QueueLimit: tt.queueLimit})
+			start := time.Now()
+			l.send(t, 0, tt.sent)
+			got := l.settle(t)
+
+			if want := sequence(0, tt.want); !reflect.DeepEqual(got, want) {
+				t.Errorf("received %v, want %v", got, want)
+			}
+			// The packets serialize: the last one is on the wire after all the others
+			if elapsed, min := time.Since(start), time.Duration(tt.want)*txTime; elapsed < min {
+				t.Errorf("%d packets through in %v, want at least %v", tt.want, elapsed, min)
+			}
+		})
+	}
+}
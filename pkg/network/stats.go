@@ -0,0 +1,157 @@
+package network
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// maxTrackedPeers caps per-peer counters so a scan or spoofed sources
+// can't grow them without bound; further peers only count in the totals
+const maxTrackedPeers = 1024
+
+// TransportStats is a snapshot of a transport's counters since it was created
+type TransportStats struct {
+	PacketsSent     uint64
+	BytesSent       uint64
+	PacketsReceived uint64
+	BytesReceived   uint64
+	DroppedFull     uint64 // Received but shed from a full receive queue class
+	Malformed       uint64 // Received but unparseable (incl. unsupported versions)
+	WriteErrors     uint64
+
+	SentByType     map[uint8]TypeCounters  // Key: protocol.PacketType*
+	ReceivedByType map[uint8]TypeCounters  // Key: protocol.PacketType*
+	Peers          map[string]PeerCounters // Key: "[ipv6]:port"
+}
+
+// TypeCounters counts packets of one packet type
+type TypeCounters struct {
+	Packets uint64
+	Bytes   uint64
+}
+
+// PeerCounters counts traffic with one remote address
+type PeerCounters struct {
+	PacketsSent     uint64
+	BytesSent       uint64
+	PacketsReceived uint64
+	BytesReceived   uint64
+	WriteErrors     uint64
+	LastSeen        time.Time // Last packet received from the peer (zero = never)
+}
+
+// transportCounters accumulates TransportStats for a transport
+type transportCounters struct {
+	mu    sync.Mutex
+	stats TransportStats
+}
+
+// recordSent counts a packet written to addr
+func (c *transportCounters) recordSent(packetType uint8, n int, addr *net.UDPAddr) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stats.PacketsSent++
+	c.stats.BytesSent += uint64(n)
+	c.stats.SentByType = addTypeCount(c.stats.SentByType, packetType, n)
+
+	if peer, ok := c.peer(addr); ok {
+		peer.PacketsSent++
+		peer.BytesSent += uint64(n)
+		c.stats.Peers[addr.String()] = peer
+	}
+}
+
+// recordWriteError counts a failed write to addr
+func (c *transportCounters) recordWriteError(addr *net.UDPAddr) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stats.WriteErrors++
+	if peer, ok := c.peer(addr); ok {
+		peer.WriteErrors++
+		c.stats.Peers[addr.String()] = peer
+	}
+}
+
+// recordReceived counts a parsed packet from addr
+func (c *transportCounters) recordReceived(packetType uint8, n int, addr *net.UDPAddr) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stats.PacketsReceived++
+	c.stats.BytesReceived += uint64(n)
+	c.stats.ReceivedByType = addTypeCount(c.stats.ReceivedByType, packetType, n)
+
+	if peer, ok := c.peer(addr); ok {
+		peer.PacketsReceived++
+		peer.BytesReceived += uint64(n)
+		peer.LastSeen = time.Now()
+		c.stats.Peers[addr.String()] = peer
+	}
+}
+
+// recordDroppedFull counts a packet dropped because nobody drained the queue
+func (c *transportCounters) recordDroppedFull() {
+	c.mu.Lock()
+	c.stats.DroppedFull++
+	c.mu.Unlock()
+}
+
+// recordMalformed counts a datagram that failed to unmarshal
+func (c *transportCounters) recordMalformed() {
+	c.mu.Lock()
+	c.stats.Malformed++
+	c.mu.Unlock()
+}
+
+// peer returns the counters for addr, false once the peer table is full
+// Caller must hold c.mu.
+func (c *transportCounters) peer(addr *net.UDPAddr) (PeerCounters, bool) {
+	if addr == nil {
+		return PeerCounters{}, false
+	}
+	if c.stats.Peers == nil {
+		c.stats.Peers = make(map[string]PeerCounters)
+	}
+
+	peer, ok := c.stats.Peers[addr.String()]
+	if !ok && len(c.stats.Peers) >= maxTrackedPeers {
+		return PeerCounters{}, false
+	}
+	return peer, true
+}
+
+// snapshot returns a copy of the counters that is safe to keep
+func (c *transportCounters) snapshot() TransportStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.stats
+	s.SentByType = make(map[uint8]TypeCounters, len(c.stats.SentByType))
+	for k, v := range c.stats.SentByType {
+		s.SentByType[k] = v
+	}
+	s.ReceivedByType = make(map[uint8]TypeCounters, len(c.stats.ReceivedByType))
+	for k, v := range c.stats.ReceivedByType {
+		s.ReceivedByType[k] = v
+	}
+	s.Peers = make(map[string]PeerCounters, len(c.stats.Peers))
+	for k, v := range c.stats.Peers {
+		s.Peers[k] = v
+	}
+	return s
+}
+
+// addTypeCount adds one packet of n bytes to a per-type map
+func addTypeCount(m map[uint8]TypeCounters, packetType uint8, n int) map[uint8]TypeCounters {
+	if m == nil {
+		m = make(map[uint8]TypeCounters)
+	}
+	tc := m[packetType]
+	tc.Packets++
+	tc.Bytes += uint64(n)
+	m[packetType] = tc
+	return m
+}
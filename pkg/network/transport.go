@@ -1,28 +1,77 @@
 package network
 
 import (
+	"errors"
 	"fmt"
 	"net"
 	"sync"
 	"time"
 
 	"github.com/meshradio/meshradio/pkg/protocol"
+	"golang.org/x/net/ipv6"
 )
 
-// Transport handles UDP-based packet transmission
-type Transport struct {
+// Transport sends and receives protocol packets
+// UDPTransport is the real network; SimNetwork provides in-memory endpoints
+type Transport interface {
+	// Start begins receiving packets
+	Start() error
+
+	// Stop closes the transport; Receive returns an error once drained
+	Stop() error
+
+	// Send sends a packet to the specified IPv6 address and port
+	Send(packet *protocol.Packet, targetIPv6 net.IP, port int) error
+
+	// SendBatch sends one packet to many destinations, marshaling it once.
+	// A failing destination never stops the rest of the batch; the error
+	// is only set when the packet itself cannot be marshaled or the socket
+	// fails without naming a destination.
+	SendBatch(packet *protocol.Packet, targets []*net.UDPAddr) ([]SendError, error)
+
+	// Receive blocks for the next packet and the address it was sent from
+	Receive() (*protocol.Packet, *net.UDPAddr, error)
+
+	// LocalAddr returns the address packets are received on
+	LocalAddr() *net.UDPAddr
+
+	// Stats returns a snapshot of the traffic and drop counters
+	Stats() TransportStats
+}
+
+// SendError records a destination that failed during SendBatch
+type SendError struct {
+	Addr *net.UDPAddr
+	Err  error
+}
+
+// UDPTransport handles UDP-based packet transmission
+type UDPTransport struct {
 	conn       *net.UDPConn
+	pconn      *ipv6.PacketConn // Same socket, for sendmmsg batches
 	localAddr  *net.UDPAddr
 	remoteAddr *net.UDPAddr
 	running    bool
 	mu         sync.Mutex
 
-	// Packet receive channel
-	packets chan *protocol.Packet
+	// Reused SendBatch messages
+	batch   []ipv6.Message
+	batchMu sync.Mutex
+
+	counters transportCounters
+
+	// Received packets, served by priority
+	packets *PacketQueue
+}
+
+// datagram is a received packet with the address it actually came from
+type datagram struct {
+	packet *protocol.Packet
+	from   *net.UDPAddr
 }
 
-// NewTransport creates a new transport layer
-func NewTransport(localPort int) (*Transport, error) {
+// NewTransport creates a new UDP transport bound to localPort on all addresses
+func NewTransport(localPort int) (*UDPTransport, error) {
 	addr := &net.UDPAddr{
 		IP:   net.IPv6zero,
 		Port: localPort,
@@ -33,15 +82,16 @@ func NewTransport(localPort int) (*Transport, error) {
 		return nil, fmt.Errorf("failed to create UDP socket: %w", err)
 	}
 
-	return &Transport{
+	return &UDPTransport{
 		conn:      conn,
+		pconn:     ipv6.NewPacketConn(conn),
 		localAddr: addr,
-		packets:   make(chan *protocol.Packet, 100),
+		packets:   NewPacketQueue(100),
 	}, nil
 }
 
 // Start begins listening for packets
-func (t *Transport) Start() error {
+func (t *UDPTransport) Start() error {
 	t.mu.Lock()
 	if t.running {
 		t.mu.Unlock()
@@ -55,7 +105,7 @@ func (t *Transport) Start() error {
 }
 
 // Stop stops the transport
-func (t *Transport) Stop() error {
+func (t *UDPTransport) Stop() error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
@@ -68,7 +118,7 @@ func (t *Transport) Stop() error {
 }
 
 // Send sends a packet to the specified IPv6 address
-func (t *Transport) Send(packet *protocol.Packet, targetIPv6 net.IP, port int) error {
+func (t *UDPTransport) Send(packet *protocol.Packet, targetIPv6 net.IP, port int) error {
 	data, err := packet.Marshal()
 	if err != nil {
 		return fmt.Errorf("failed to marshal packet: %w", err)
@@ -81,23 +131,95 @@ func (t *Transport) Send(packet *protocol.Packet, targetIPv6 net.IP, port int) e
 
 	_, err = t.conn.WriteToUDP(data, addr)
 	if err != nil {
+		t.counters.recordWriteError(addr)
 		return fmt.Errorf("failed to send packet: %w", err)
 	}
+	t.counters.recordSent(packet.Type, len(data), addr)
 
 	return nil
 }
 
-// Receive returns the next received packet
-func (t *Transport) Receive() (*protocol.Packet, error) {
-	packet, ok := <-t.packets
+// SendBatch sends one packet to many destinations with as few syscalls as
+// possible (sendmmsg on Linux, one write per message elsewhere)
+func (t *UDPTransport) SendBatch(packet *protocol.Packet, targets []*net.UDPAddr) ([]SendError, error) {
+	if len(targets) == 0 {
+		return nil, nil
+	}
+
+	data, err := packet.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal packet: %w", err)
+	}
+
+	t.batchMu.Lock()
+	defer t.batchMu.Unlock()
+
+	// Every message shares the one marshaled buffer
+	if cap(t.batch) < len(targets) {
+		t.batch = make([]ipv6.Message, len(targets))
+	}
+	msgs := t.batch[:len(targets)]
+	for i, addr := range targets {
+		if len(msgs[i].Buffers) != 1 {
+			msgs[i].Buffers = make([][]byte, 1)
+		}
+		msgs[i].Buffers[0] = data
+		msgs[i].Addr = addr
+	}
+
+	var failed []SendError
+	var batchErr error
+	for sent := 0; sent < len(msgs); {
+		n, err := t.pconn.WriteBatch(msgs[sent:], 0)
+		if n < 0 {
+			n = 0
+		}
+		if err == nil && n == 0 {
+			err = fmt.Errorf("no messages written")
+		}
+		for _, addr := range targets[sent : sent+n] {
+			t.counters.recordSent(packet.Type, len(data), addr)
+		}
+		sent += n
+
+		if err != nil && sent == len(msgs) {
+			// Everything went out, so the error names no destination
+			batchErr = fmt.Errorf("failed to send batch: %w", err)
+			break
+		}
+		if err != nil {
+			// targets[sent] is the one the kernel refused; skip it and carry on
+			t.counters.recordWriteError(targets[sent])
+			failed = append(failed, SendError{
+				Addr: targets[sent],
+				Err:  fmt.Errorf("failed to send packet: %w", err),
+			})
+			sent++
+		}
+	}
+
+	// Don't pin the last frame or subscriber addresses until the next call
+	for i := range targets {
+		t.batch[i].Buffers[0] = nil
+		t.batch[i].Addr = nil
+	}
+
+	return failed, batchErr
+}
+
+// Receive returns the next received packet and the UDP address it was sent from.
+// Unlike the addresses inside payloads, the sender address cannot be chosen
+// freely by a remote host that wants to receive the replies.
+func (t *UDPTransport) Receive() (*protocol.Packet, *net.UDPAddr, error) {
+	packet, from, ok := t.packets.Pop()
 	if !ok {
-		return nil, fmt.Errorf("transport closed")
+		return nil, nil, fmt.Errorf("transport closed")
 	}
-	return packet, nil
+	return packet, from, nil
 }
 
 // SetRemote sets the remote address for sending
-func (t *Transport) SetRemote(ipv6 net.IP, port int) {
+func (t *UDPTransport) SetRemote(ipv6 net.IP, port int) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
@@ -108,14 +230,14 @@ func (t *Transport) SetRemote(ipv6 net.IP, port int) {
 }
 
 // receiveLoop continuously receives packets
-func (t *Transport) receiveLoop() {
+func (t *UDPTransport) receiveLoop() {
 	buffer := make([]byte, 65535) // Max UDP packet size
 
 	for t.running {
 		// Set read deadline to allow checking running status
 		t.conn.SetReadDeadline(time.Now().Add(1 * time.Second))
 
-		n, _, err := t.conn.ReadFromUDP(buffer)
+		n, from, err := t.conn.ReadFromUDP(buffer)
 		if err != nil {
 			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
 				continue // Timeout, check running status
@@ -129,22 +251,36 @@ func (t *Transport) receiveLoop() {
 		// Parse packet
 		packet, err := protocol.Unmarshal(buffer[:n])
 		if err != nil {
-			fmt.Printf("Error unmarshaling packet: %v\n", err)
+			t.counters.recordMalformed()
+			if errors.Is(err, protocol.ErrVersionMismatch) {
+				fmt.Printf("Dropping packet with unsupported protocol version %d\n", buffer[0])
+			} else {
+				fmt.Printf("Error unmarshaling packet: %v\n", err)
+			}
 			continue
 		}
 
-		// Queue packet
-		select {
-		case t.packets <- packet:
-		default:
-			// Channel full, drop packet
+		t.counters.recordReceived(packet.Type, n, from)
+
+		// Queue packet; a full class sheds its oldest packet
+		if t.packets.Push(packet, from) {
+			t.counters.recordDroppedFull()
 		}
 	}
 
-	close(t.packets)
+	t.packets.Close()
 }
 
 // LocalAddr returns the local address
-func (t *Transport) LocalAddr() *net.UDPAddr {
+// When bound to port 0 this reports the port the kernel actually assigned
+func (t *UDPTransport) LocalAddr() *net.UDPAddr {
+	if addr, ok := t.conn.LocalAddr().(*net.UDPAddr); ok {
+		return addr
+	}
 	return t.localAddr
 }
+
+// Stats returns a snapshot of the traffic and drop counters
+func (t *UDPTransport) Stats() TransportStats {
+	return t.counters.snapshot()
+}
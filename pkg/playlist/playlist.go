@@ -0,0 +1,174 @@
+package playlist
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/go-mp3"
+	"github.com/meshradio/meshradio/pkg/audio"
+	"github.com/meshradio/meshradio/pkg/protocol"
+)
+
+// Playlist cycles through the music files of a directory
+type Playlist struct {
+	files   []string
+	current int
+}
+
+// Track is a playlist entry opened for playback
+type Track struct {
+	Index    int
+	File     string
+	Duration time.Duration
+	Rate     int
+	Metadata protocol.Metadata
+	Source   *audio.FFmpegSource
+}
+
+// Scan builds a playlist of the MP3 files below dir
+func Scan(dir string) (*Playlist, error) {
+	var files []string
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
+			ext := strings.ToLower(filepath.Ext(path))
+			if ext == ".mp3" {
+				files = append(files, path)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Playlist{
+		files:   files,
+		current: 0,
+	}, nil
+}
+
+// Files returns the files of the playlist in play order
+func (p *Playlist) Files() []string {
+	return p.files
+}
+
+// Len returns the number of files in the playlist
+func (p *Playlist) Len() int {
+	return len(p.files)
+}
+
+// OpenNext advances the playlist and opens the next playable track.
+// Its decoder is started right away so it can be swapped in without a gap.
+// stationText is shown to listeners along with the track position.
+// Returns io.EOF at the end of the playlist when not looping.
+func (p *Playlist) OpenNext(config audio.StreamConfig, stationText string, loop bool) (*Track, error) {
+	var lastErr error = io.EOF
+
+	// Try each file at most once per call so a playlist of broken files can't spin
+	for tries := 0; tries < len(p.files); tries++ {
+		if p.current >= len(p.files) {
+			if !loop {
+				return nil, lastErr
+			}
+			fmt.Println("🔄 Looping playlist...")
+			p.current = 0
+		}
+
+		i := p.current
+		file := p.files[i]
+		p.current++
+
+		// FFmpeg decodes more formats than go-mp3 and resamples for us
+		source, err := audio.NewFFmpegSource(file, config)
+		if err == nil {
+			err = source.Start()
+		}
+		if err != nil {
+			lastErr = fmt.Errorf("failed to open %s: %w", filepath.Base(file), err)
+			fmt.Printf("   ❌ Skipping: %v\n", lastErr)
+			continue
+		}
+
+		duration, sampleRate := MP3Info(file)
+
+		// Now-playing metadata for listeners
+		md := TrackMetadata(file, duration)
+		md.StationText = fmt.Sprintf("%s - track %d of %d", stationText, i+1, len(p.files))
+
+		return &Track{
+			Index:    i,
+			File:     file,
+			Duration: duration,
+			Rate:     sampleRate,
+			Metadata: md,
+			Source:   source,
+		}, nil
+	}
+
+	return nil, lastErr
+}
+
+// Announce prints the now-playing banner for a track
+func (t *Track) Announce(total int) {
+	fmt.Printf("▶️  Now playing [%d/%d]: %s\n", t.Index+1, total, filepath.Base(t.File))
+	fmt.Printf("   Duration: %s | Sample Rate: %d Hz\n", t.Duration.Round(time.Second), t.Rate)
+}
+
+// TrackMetadata derives now-playing metadata from the file path.
+// File names like "001. Artist - Title.mp3" are split into artist and title,
+// and the containing directory is used as the album.
+func TrackMetadata(file string, duration time.Duration) protocol.Metadata {
+	name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+
+	// Strip leading track numbers ("001. ", "01 - ")
+	trimmed := strings.TrimLeft(name, "0123456789")
+	if trimmed != name {
+		trimmed = strings.TrimLeft(trimmed, ".-_ ")
+		if trimmed != "" {
+			name = trimmed
+		}
+	}
+
+	md := protocol.Metadata{
+		Title:    name,
+		Album:    filepath.Base(filepath.Dir(file)),
+		Duration: duration,
+	}
+	if parts := strings.SplitN(name, " - ", 2); len(parts) == 2 {
+		md.Artist = strings.TrimSpace(parts[0])
+		md.Title = strings.TrimSpace(parts[1])
+	}
+
+	return md
+}
+
+// MP3Info returns the duration and sample rate of an MP3 file (zero if unreadable)
+func MP3Info(filepath string) (time.Duration, int) {
+	f, err := os.Open(filepath)
+	if err != nil {
+		return 0, 0
+	}
+	defer f.Close()
+
+	decoder, err := mp3.NewDecoder(f)
+	if err != nil {
+		return 0, 0
+	}
+
+	sampleRate := decoder.SampleRate()
+	length := decoder.Length()
+	duration := time.Duration(length) * time.Second / time.Duration(sampleRate) / 4 // 4 = 2 channels * 2 bytes per sample
+
+	return duration, sampleRate
+}
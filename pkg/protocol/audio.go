@@ -18,7 +18,7 @@ type AudioPacket struct {
 	SampleRate     uint8  // Encoded value (48kHz = 48, 44.1kHz = 44, etc.)
 	Channels       uint8
 	Bitrate        uint8  // In kbps (64 = 64kbps)
-	FrameTimestamp uint32
+	FrameTimestamp uint32 // Media timestamp in samples (v1 senders: wall-clock milliseconds)
 	AudioData      []byte
 }
 
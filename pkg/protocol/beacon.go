@@ -0,0 +1,64 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+// BeaconPayload represents a periodic station announcement
+// The station callsign travels in the packet header
+type BeaconPayload struct {
+	Group         [32]byte
+	Priority      uint8  // 0-3, same scale as packet priority
+	CodecType     uint8  // Codec* constant
+	SampleRate    uint8  // Encoded like AudioPacket (48kHz = 48)
+	Channels      uint8
+	Bitrate       uint16 // In kbps
+	ListenerCount uint16
+	Uptime        uint32 // Seconds since the broadcaster started
+	Interval      uint32 // Milliseconds until the next beacon
+}
+
+// MarshalBeacon encodes beacon payload to bytes
+func MarshalBeacon(bp *BeaconPayload) []byte {
+	buf := make([]byte, 48) // 32 + 1 + 1 + 1 + 1 + 2 + 2 + 4 + 4
+
+	copy(buf[0:32], bp.Group[:])
+	buf[32] = bp.Priority
+	buf[33] = bp.CodecType
+	buf[34] = bp.SampleRate
+	buf[35] = bp.Channels
+	binary.BigEndian.PutUint16(buf[36:38], bp.Bitrate)
+	binary.BigEndian.PutUint16(buf[38:40], bp.ListenerCount)
+	binary.BigEndian.PutUint32(buf[40:44], bp.Uptime)
+	binary.BigEndian.PutUint32(buf[44:48], bp.Interval)
+
+	return buf
+}
+
+// UnmarshalBeacon decodes beacon payload from bytes
+func UnmarshalBeacon(data []byte) (*BeaconPayload, error) {
+	if len(data) < 48 {
+		return nil, ErrInvalidPayload
+	}
+
+	bp := &BeaconPayload{
+		Priority:      data[32],
+		CodecType:     data[33],
+		SampleRate:    data[34],
+		Channels:      data[35],
+		Bitrate:       binary.BigEndian.Uint16(data[36:38]),
+		ListenerCount: binary.BigEndian.Uint16(data[38:40]),
+		Uptime:        binary.BigEndian.Uint32(data[40:44]),
+		Interval:      binary.BigEndian.Uint32(data[44:48]),
+	}
+
+	copy(bp.Group[:], data[0:32])
+
+	return bp, nil
+}
+
+// IntervalDuration returns the advertised beacon interval
+func (bp *BeaconPayload) IntervalDuration() time.Duration {
+	return time.Duration(bp.Interval) * time.Millisecond
+}
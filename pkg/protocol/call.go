@@ -0,0 +1,94 @@
+package protocol
+
+import (
+	"encoding/binary"
+)
+
+// MaxCallMessage is the longest free-form text a call can carry
+const MaxCallMessage = 128
+
+// Call reply results
+const (
+	CallAccepted uint8 = 0x01
+	CallDeclined uint8 = 0x02
+)
+
+// CallPayload represents a CQ (PacketTypeCallCQ) or selective call
+// (PacketTypeCallSelective). The caller's callsign travels in the packet header.
+type CallPayload struct {
+	CallID         uint32
+	Group          [32]byte // Group the caller is on
+	TargetCallsign [16]byte // Selective call target (all zeros for CQ)
+	TargetIPv6     [16]byte // Selective call target (all zeros = match by callsign)
+	StreamPort     uint16   // Caller's broadcast port (0 = caller is not streaming)
+	ReplyPort      uint16   // Where to send the CallReply
+	Message        string   // Free-form text, truncated to MaxCallMessage bytes
+}
+
+// CallReplyPayload answers a call
+type CallReplyPayload struct {
+	CallID uint32
+	Result uint8 // CallAccepted or CallDeclined
+}
+
+// MarshalCall encodes call payload to bytes
+func MarshalCall(cp *CallPayload) []byte {
+	message := cp.Message
+	if len(message) > MaxCallMessage {
+		message = message[:MaxCallMessage]
+	}
+
+	buf := make([]byte, 72+len(message)) // 4 + 32 + 16 + 16 + 2 + 2 + message
+
+	binary.BigEndian.PutUint32(buf[0:4], cp.CallID)
+	copy(buf[4:36], cp.Group[:])
+	copy(buf[36:52], cp.TargetCallsign[:])
+	copy(buf[52:68], cp.TargetIPv6[:])
+	binary.BigEndian.PutUint16(buf[68:70], cp.StreamPort)
+	binary.BigEndian.PutUint16(buf[70:72], cp.ReplyPort)
+	copy(buf[72:], message)
+
+	return buf
+}
+
+// UnmarshalCall decodes call payload from bytes
+func UnmarshalCall(data []byte) (*CallPayload, error) {
+	if len(data) < 72 {
+		return nil, ErrInvalidPayload
+	}
+
+	cp := &CallPayload{
+		CallID:     binary.BigEndian.Uint32(data[0:4]),
+		StreamPort: binary.BigEndian.Uint16(data[68:70]),
+		ReplyPort:  binary.BigEndian.Uint16(data[70:72]),
+		Message:    string(data[72:]),
+	}
+
+	copy(cp.Group[:], data[4:36])
+	copy(cp.TargetCallsign[:], data[36:52])
+	copy(cp.TargetIPv6[:], data[52:68])
+
+	return cp, nil
+}
+
+// MarshalCallReply encodes call reply payload to bytes
+func MarshalCallReply(cr *CallReplyPayload) []byte {
+	buf := make([]byte, 5) // 4 + 1
+
+	binary.BigEndian.PutUint32(buf[0:4], cr.CallID)
+	buf[4] = cr.Result
+
+	return buf
+}
+
+// UnmarshalCallReply decodes call reply payload from bytes
+func UnmarshalCallReply(data []byte) (*CallReplyPayload, error) {
+	if len(data) < 5 {
+		return nil, ErrInvalidPayload
+	}
+
+	return &CallReplyPayload{
+		CallID: binary.BigEndian.Uint32(data[0:4]),
+		Result: data[4],
+	}, nil
+}
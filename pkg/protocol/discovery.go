@@ -0,0 +1,128 @@
+package protocol
+
+import (
+	"encoding/binary"
+)
+
+// MaxDiscoveryRecords caps the records in one response so it fits a single datagram
+const MaxDiscoveryRecords = 16
+
+// stationRecordSize is the encoded size of one StationRecord
+const stationRecordSize = 73 // 16 + 2 + 16 + 32 + 1 + 1 + 2 + 2 + 1
+
+// DiscoveryRequestPayload asks a node which stations it hosts or has heard of
+type DiscoveryRequestPayload struct {
+	RequestID uint32   // Echoed in the response
+	ReplyIPv6 [16]byte // Where to send the response
+	ReplyPort uint16
+	Group     [32]byte // Group filter (all zeros = any group)
+}
+
+// StationRecord describes one station in a discovery response
+type StationRecord struct {
+	IPv6      [16]byte
+	Port      uint16
+	Callsign  [16]byte
+	Group     [32]byte
+	Priority  uint8  // 0-3, same scale as packet priority
+	CodecType uint8  // Codec* constant
+	Bitrate   uint16 // In kbps (0 = unknown)
+	TTL       uint16 // Seconds the record may be cached
+	Hops      uint8  // 0 = hosted by the responder, >0 = heard of via other nodes
+}
+
+// DiscoveryResponsePayload lists stations known to the responder
+type DiscoveryResponsePayload struct {
+	RequestID uint32
+	Records   []StationRecord
+}
+
+// MarshalDiscoveryRequest encodes discovery request payload to bytes
+func MarshalDiscoveryRequest(dr *DiscoveryRequestPayload) []byte {
+	buf := make([]byte, 54) // 4 + 16 + 2 + 32
+
+	binary.BigEndian.PutUint32(buf[0:4], dr.RequestID)
+	copy(buf[4:20], dr.ReplyIPv6[:])
+	binary.BigEndian.PutUint16(buf[20:22], dr.ReplyPort)
+	copy(buf[22:54], dr.Group[:])
+
+	return buf
+}
+
+// UnmarshalDiscoveryRequest decodes discovery request payload from bytes
+func UnmarshalDiscoveryRequest(data []byte) (*DiscoveryRequestPayload, error) {
+	if len(data) < 54 {
+		return nil, ErrInvalidPayload
+	}
+
+	dr := &DiscoveryRequestPayload{
+		RequestID: binary.BigEndian.Uint32(data[0:4]),
+		ReplyPort: binary.BigEndian.Uint16(data[20:22]),
+	}
+
+	copy(dr.ReplyIPv6[:], data[4:20])
+	copy(dr.Group[:], data[22:54])
+
+	return dr, nil
+}
+
+// MarshalDiscoveryResponse encodes discovery response payload to bytes
+// Records beyond MaxDiscoveryRecords are dropped
+func MarshalDiscoveryResponse(dr *DiscoveryResponsePayload) []byte {
+	records := dr.Records
+	if len(records) > MaxDiscoveryRecords {
+		records = records[:MaxDiscoveryRecords]
+	}
+
+	buf := make([]byte, 5+len(records)*stationRecordSize)
+	binary.BigEndian.PutUint32(buf[0:4], dr.RequestID)
+	buf[4] = uint8(len(records))
+
+	for i, r := range records {
+		rec := buf[5+i*stationRecordSize : 5+(i+1)*stationRecordSize]
+		copy(rec[0:16], r.IPv6[:])
+		binary.BigEndian.PutUint16(rec[16:18], r.Port)
+		copy(rec[18:34], r.Callsign[:])
+		copy(rec[34:66], r.Group[:])
+		rec[66] = r.Priority
+		rec[67] = r.CodecType
+		binary.BigEndian.PutUint16(rec[68:70], r.Bitrate)
+		binary.BigEndian.PutUint16(rec[70:72], r.TTL)
+		rec[72] = r.Hops
+	}
+
+	return buf
+}
+
+// UnmarshalDiscoveryResponse decodes discovery response payload from bytes
+func UnmarshalDiscoveryResponse(data []byte) (*DiscoveryResponsePayload, error) {
+	if len(data) < 5 {
+		return nil, ErrInvalidPayload
+	}
+
+	count := int(data[4])
+	if len(data) < 5+count*stationRecordSize {
+		return nil, ErrInvalidPayload
+	}
+
+	dr := &DiscoveryResponsePayload{
+		RequestID: binary.BigEndian.Uint32(data[0:4]),
+		Records:   make([]StationRecord, count),
+	}
+
+	for i := range dr.Records {
+		rec := data[5+i*stationRecordSize : 5+(i+1)*stationRecordSize]
+		r := &dr.Records[i]
+		copy(r.IPv6[:], rec[0:16])
+		r.Port = binary.BigEndian.Uint16(rec[16:18])
+		copy(r.Callsign[:], rec[18:34])
+		copy(r.Group[:], rec[34:66])
+		r.Priority = rec[66]
+		r.CodecType = rec[67]
+		r.Bitrate = binary.BigEndian.Uint16(rec[68:70])
+		r.TTL = binary.BigEndian.Uint16(rec[70:72])
+		r.Hops = rec[72]
+	}
+
+	return dr, nil
+}
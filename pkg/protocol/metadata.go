@@ -0,0 +1,143 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"time"
+	"unicode/utf8"
+)
+
+// Metadata payload version
+const MetadataVersion uint8 = 0x01
+
+// Metadata TLV tags
+const (
+	MetaTagTitle       uint8 = 0x01
+	MetaTagArtist      uint8 = 0x02
+	MetaTagAlbum       uint8 = 0x03
+	MetaTagDuration    uint8 = 0x04 // uint32 milliseconds
+	MetaTagPosition    uint8 = 0x05 // uint32 milliseconds
+	MetaTagStationText uint8 = 0x06
+)
+
+// Metadata represents now-playing information carried by PacketTypeMetadata
+type Metadata struct {
+	Title       string
+	Artist      string
+	Album       string
+	Duration    time.Duration // Track length (0 = unknown / live)
+	Position    time.Duration // Playback position when the payload was built
+	StationText string        // Free-form station message
+}
+
+// MarshalMetadata encodes metadata to a versioned TLV payload
+// Layout: [version:1] then repeated [tag:1][length:1][value:length]
+// Text fields longer than 255 bytes are truncated, empty fields are omitted
+func MarshalMetadata(md *Metadata) []byte {
+	buf := []byte{MetadataVersion}
+
+	buf = appendTextTLV(buf, MetaTagTitle, md.Title)
+	buf = appendTextTLV(buf, MetaTagArtist, md.Artist)
+	buf = appendTextTLV(buf, MetaTagAlbum, md.Album)
+	if md.Duration > 0 {
+		buf = appendMillisTLV(buf, MetaTagDuration, md.Duration)
+	}
+	if md.Position > 0 {
+		buf = appendMillisTLV(buf, MetaTagPosition, md.Position)
+	}
+	buf = appendTextTLV(buf, MetaTagStationText, md.StationText)
+
+	return buf
+}
+
+// UnmarshalMetadata decodes a TLV metadata payload
+// Unknown tags are skipped so newer versions stay readable
+func UnmarshalMetadata(data []byte) (*Metadata, error) {
+	if len(data) < 1 || data[0] == 0 {
+		return nil, ErrInvalidPayload
+	}
+
+	md := &Metadata{}
+	pos := 1
+	for pos < len(data) {
+		if pos+2 > len(data) {
+			return nil, ErrInvalidPayload
+		}
+		tag := data[pos]
+		length := int(data[pos+1])
+		pos += 2
+
+		if pos+length > len(data) {
+			return nil, ErrInvalidPayload
+		}
+		value := data[pos : pos+length]
+		pos += length
+
+		switch tag {
+		case MetaTagTitle:
+			md.Title = string(value)
+		case MetaTagArtist:
+			md.Artist = string(value)
+		case MetaTagAlbum:
+			md.Album = string(value)
+		case MetaTagDuration:
+			if length == 4 {
+				md.Duration = time.Duration(binary.BigEndian.Uint32(value)) * time.Millisecond
+			}
+		case MetaTagPosition:
+			if length == 4 {
+				md.Position = time.Duration(binary.BigEndian.Uint32(value)) * time.Millisecond
+			}
+		case MetaTagStationText:
+			md.StationText = string(value)
+		}
+	}
+
+	return md, nil
+}
+
+// At returns a copy of the metadata with Position advanced by elapsed,
+// capped at Duration when the track length is known
+func (md Metadata) At(elapsed time.Duration) Metadata {
+	md.Position += elapsed
+	if md.Duration > 0 && md.Position > md.Duration {
+		md.Position = md.Duration
+	}
+	return md
+}
+
+// DisplayTitle returns "Artist - Title", or whichever of the two is set
+func (md Metadata) DisplayTitle() string {
+	switch {
+	case md.Artist != "" && md.Title != "":
+		return md.Artist + " - " + md.Title
+	case md.Title != "":
+		return md.Title
+	default:
+		return md.Artist
+	}
+}
+
+// appendTextTLV appends a text field (skipped when empty)
+func appendTextTLV(buf []byte, tag uint8, value string) []byte {
+	if value == "" {
+		return buf
+	}
+	if len(value) > 255 {
+		// Cut on a rune boundary so the text stays valid UTF-8
+		cut := 255
+		for cut > 0 && !utf8.RuneStart(value[cut]) {
+			cut--
+		}
+		value = value[:cut]
+	}
+	buf = append(buf, tag, uint8(len(value)))
+	return append(buf, value...)
+}
+
+// appendMillisTLV appends a duration field as uint32 milliseconds
+func appendMillisTLV(buf []byte, tag uint8, d time.Duration) []byte {
+	var value [4]byte
+	binary.BigEndian.PutUint32(value[:], uint32(d/time.Millisecond))
+	buf = append(buf, tag, 4)
+	return append(buf, value[:]...)
+}
@@ -0,0 +1,69 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+// Off-air reasons
+const (
+	OffAirShutdown    uint8 = 0x00 // Broadcaster stopped
+	OffAirSourceEnded uint8 = 0x01 // Audio source ran out (end of file or stream)
+	OffAirPreempted   uint8 = 0x02 // Another broadcast took over
+)
+
+// OffAirPayload tells listeners a stream went off air on purpose
+// Listeners stop treating the silence as packet loss until audio resumes.
+type OffAirPayload struct {
+	Reason uint8
+	Group  [32]byte
+	BackAt uint64 // Unix seconds the station expects to be back (0 = unknown)
+}
+
+// MarshalOffAir encodes off-air payload to bytes
+func MarshalOffAir(oa *OffAirPayload) []byte {
+	buf := make([]byte, 41) // 1 + 32 + 8
+
+	buf[0] = oa.Reason
+	copy(buf[1:33], oa.Group[:])
+	binary.BigEndian.PutUint64(buf[33:41], oa.BackAt)
+
+	return buf
+}
+
+// UnmarshalOffAir decodes off-air payload from bytes
+func UnmarshalOffAir(data []byte) (*OffAirPayload, error) {
+	if len(data) < 41 {
+		return nil, ErrInvalidPayload
+	}
+
+	oa := &OffAirPayload{
+		Reason: data[0],
+		BackAt: binary.BigEndian.Uint64(data[33:41]),
+	}
+	copy(oa.Group[:], data[1:33])
+
+	return oa, nil
+}
+
+// BackAtTime returns when the station expects to be back (zero = unknown)
+func (oa *OffAirPayload) BackAtTime() time.Time {
+	if oa.BackAt == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(oa.BackAt), 0)
+}
+
+// OffAirReasonString returns a human-readable off-air reason
+func OffAirReasonString(reason uint8) string {
+	switch reason {
+	case OffAirShutdown:
+		return "shutdown"
+	case OffAirSourceEnded:
+		return "source ended"
+	case OffAirPreempted:
+		return "preempted"
+	default:
+		return "unknown reason"
+	}
+}
@@ -6,8 +6,11 @@ import (
 	"time"
 )
 
-// Protocol version
-const Version uint8 = 0x01
+// Protocol versions
+const (
+	Version   uint8 = 0x02 // Current version: 32-bit sequence, media timestamp and stream id
+	VersionV1 uint8 = 0x01 // 8-bit sequence numbers only, still accepted from older peers
+)
 
 // Packet types
 const (
@@ -18,27 +21,36 @@ const (
 	PacketTypeCallSelective  uint8 = 0x04
 	PacketTypeDiscoveryReq   uint8 = 0x05
 	PacketTypeDiscoveryResp  uint8 = 0x06
+	PacketTypeCallReply      uint8 = 0x07
 	PacketTypeSignalReport   uint8 = 0x09
 	PacketTypeEmergency      uint8 = 0x0A
+	PacketTypeOffAir         uint8 = 0x0B
 
 	// Subscription-based streaming (MVP)
-	PacketTypeSubscribe      uint8 = 0x10
-	PacketTypeHeartbeat      uint8 = 0x11
-	PacketTypeUnsubscribe    uint8 = 0x12
+	PacketTypeSubscribe          uint8 = 0x10
+	PacketTypeHeartbeat          uint8 = 0x11
+	PacketTypeUnsubscribe        uint8 = 0x12
+	PacketTypeSubscribeAck       uint8 = 0x13
+	PacketTypeSubscribeChallenge uint8 = 0x14
 )
 
 // Packet flags
 const (
 	FlagEncrypted  uint8 = 0x01 // Bit 0: Encrypted
 	FlagCompressed uint8 = 0x02 // Bit 1: Compressed
-	// Bits 2-3: Reserved
+	FlagSigned     uint8 = 0x04 // Bit 2: Ed25519 signature trailer after the payload
+	FlagComfortNoise uint8 = 0x08 // Bit 3: DTX keepalive, the sender is silent until the next audio without it
 	// Bits 4-5: Priority (0-3)
 	FlagPriority0 uint8 = 0x10 // Bit 4: Priority bit 0
 	FlagPriority1 uint8 = 0x20 // Bit 5: Priority bit 1
 	FlagPriorityMask uint8 = 0x30 // Bits 4-5: Priority mask
 )
 
-// Header size in bytes
+// MaxHops is how many relays a packet may pass; beyond that it is dropped
+// so a relay loop dies out instead of circulating
+const MaxHops uint8 = 8
+
+// Header size in bytes (same for v1 and v2, v2 uses bytes 48-59 that v1 leaves zero)
 const HeaderSize = 64
 
 // Packet represents a MeshRadio protocol packet
@@ -50,10 +62,13 @@ type Packet struct {
 	Timestamp      int64
 	SourceIPv6     [16]byte
 	Callsign       [16]byte
-	SequenceNum    uint8
+	SequenceNum    uint32 // v1 carries only the low 8 bits
 	SignalQuality  uint8
-	Reserved       uint8
+	HopCount       uint8  // Relays passed so far (0 = straight from the origin)
+	MediaTimestamp uint32 // v2: in samples at the stream's sample rate
+	StreamID       uint32 // v2: random per stream (SSRC), changes when the stream restarts
 	Payload        []byte
+	Signature      []byte // Set when FlagSigned, not counted in PayloadLength
 }
 
 // NewPacket creates a new packet with given type and payload
@@ -71,14 +86,14 @@ func NewPacket(packetType uint8, sourceIPv6 [16]byte, callsign string, payload [
 		Callsign:      callsignBytes,
 		SequenceNum:   0,
 		SignalQuality: 0,
-		Reserved:      0,
+		HopCount:      0,
 		Payload:       payload,
 	}
 }
 
 // Marshal encodes the packet to bytes
 func (p *Packet) Marshal() ([]byte, error) {
-	totalSize := HeaderSize + len(p.Payload)
+	totalSize := HeaderSize + len(p.Payload) + len(p.Signature)
 	buf := make([]byte, totalSize)
 
 	// Version (full byte)
@@ -102,18 +117,28 @@ func (p *Packet) Marshal() ([]byte, error) {
 	// Callsign (16 bytes)
 	copy(buf[29:45], p.Callsign[:])
 
-	// Sequence Number
-	buf[45] = p.SequenceNum
+	// Sequence Number (low 8 bits, all that v1 peers read)
+	buf[45] = uint8(p.SequenceNum)
 
 	// Signal Quality
 	buf[46] = p.SignalQuality
 
-	// Reserved
-	buf[47] = p.Reserved
+	// Hop count (was reserved, so older peers send 0)
+	buf[47] = p.HopCount
+
+	// v2: full sequence number, media timestamp and stream id
+	if p.Version >= 2 {
+		binary.BigEndian.PutUint32(buf[48:52], p.SequenceNum)
+		binary.BigEndian.PutUint32(buf[52:56], p.MediaTimestamp)
+		binary.BigEndian.PutUint32(buf[56:60], p.StreamID)
+	}
 
 	// Payload
 	copy(buf[HeaderSize:], p.Payload)
 
+	// Signature trailer
+	copy(buf[HeaderSize+len(p.Payload):], p.Signature)
+
 	return buf, nil
 }
 
@@ -127,6 +152,9 @@ func Unmarshal(data []byte) (*Packet, error) {
 
 	// Version (full byte)
 	p.Version = data[0]
+	if p.Version < VersionV1 || p.Version > Version {
+		return nil, ErrVersionMismatch
+	}
 
 	// Type (full byte)
 	p.Type = data[1]
@@ -147,18 +175,36 @@ func Unmarshal(data []byte) (*Packet, error) {
 	copy(p.Callsign[:], data[29:45])
 
 	// Sequence Number
-	p.SequenceNum = data[45]
+	p.SequenceNum = uint32(data[45])
 
 	// Signal Quality
 	p.SignalQuality = data[46]
 
-	// Reserved
-	p.Reserved = data[47]
+	// Hop count
+	p.HopCount = data[47]
+
+	// v2: full sequence number, media timestamp and stream id
+	if p.Version >= 2 {
+		p.SequenceNum = binary.BigEndian.Uint32(data[48:52])
+		p.MediaTimestamp = binary.BigEndian.Uint32(data[52:56])
+		p.StreamID = binary.BigEndian.Uint32(data[56:60])
+	}
+
+	// Signature trailer
+	body := data[HeaderSize:]
+	if p.Flags&FlagSigned != 0 {
+		if len(body) < SignatureSize {
+			return nil, ErrInvalidPacket
+		}
+		p.Signature = make([]byte, SignatureSize)
+		copy(p.Signature, body[len(body)-SignatureSize:])
+		body = body[:len(body)-SignatureSize]
+	}
 
 	// Payload
-	if len(data) > HeaderSize {
-		p.Payload = make([]byte, len(data)-HeaderSize)
-		copy(p.Payload, data[HeaderSize:])
+	if len(body) > 0 {
+		p.Payload = make([]byte, len(body))
+		copy(p.Payload, body)
 	}
 
 	// Validate payload length
@@ -185,6 +231,16 @@ func (p *Packet) GetCallsign() string {
 	return string(p.Callsign[:length])
 }
 
+// SequenceDelta returns how far this packet's sequence number is ahead of prev,
+// accounting for wraparound at the version's sequence width.
+// 1 is the next packet, >1 means packets were lost, <=0 is a late or duplicate packet.
+func (p *Packet) SequenceDelta(prev uint32) int64 {
+	if p.Version == VersionV1 {
+		return int64(int8(uint8(p.SequenceNum) - uint8(prev)))
+	}
+	return int64(int32(p.SequenceNum - prev))
+}
+
 // GetPriority extracts priority from packet flags (bits 4-5)
 func (p *Packet) GetPriority() uint8 {
 	return (p.Flags & FlagPriorityMask) >> 4
@@ -0,0 +1,85 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+// SignalReportPayload is a listener's periodic reception report to the broadcaster.
+// Counters cover the reporting interval only, the broadcaster keeps the totals.
+type SignalReportPayload struct {
+	ListenerIPv6    [16]byte
+	ListenerPort    uint16
+	Group           [32]byte
+	Interval        uint32 // Milliseconds covered by this report
+	PacketsReceived uint32
+	PacketsLost     uint32 // Detected from sequence number gaps
+	Jitter          uint32 // Interarrival jitter in microseconds (RFC 3550 estimator)
+	BufferLevel     uint16 // Frames queued for playout
+	BufferCapacity  uint16
+	DecodeErrors    uint32
+	Quality         uint8 // 0-100, same value as the header SignalQuality
+}
+
+// MarshalSignalReport encodes signal report payload to bytes
+func MarshalSignalReport(sr *SignalReportPayload) []byte {
+	buf := make([]byte, 75) // 16 + 2 + 32 + 4 + 4 + 4 + 4 + 2 + 2 + 4 + 1
+
+	copy(buf[0:16], sr.ListenerIPv6[:])
+	binary.BigEndian.PutUint16(buf[16:18], sr.ListenerPort)
+	copy(buf[18:50], sr.Group[:])
+	binary.BigEndian.PutUint32(buf[50:54], sr.Interval)
+	binary.BigEndian.PutUint32(buf[54:58], sr.PacketsReceived)
+	binary.BigEndian.PutUint32(buf[58:62], sr.PacketsLost)
+	binary.BigEndian.PutUint32(buf[62:66], sr.Jitter)
+	binary.BigEndian.PutUint16(buf[66:68], sr.BufferLevel)
+	binary.BigEndian.PutUint16(buf[68:70], sr.BufferCapacity)
+	binary.BigEndian.PutUint32(buf[70:74], sr.DecodeErrors)
+	buf[74] = sr.Quality
+
+	return buf
+}
+
+// UnmarshalSignalReport decodes signal report payload from bytes
+func UnmarshalSignalReport(data []byte) (*SignalReportPayload, error) {
+	if len(data) < 75 {
+		return nil, ErrInvalidPayload
+	}
+
+	sr := &SignalReportPayload{
+		ListenerPort:    binary.BigEndian.Uint16(data[16:18]),
+		Interval:        binary.BigEndian.Uint32(data[50:54]),
+		PacketsReceived: binary.BigEndian.Uint32(data[54:58]),
+		PacketsLost:     binary.BigEndian.Uint32(data[58:62]),
+		Jitter:          binary.BigEndian.Uint32(data[62:66]),
+		BufferLevel:     binary.BigEndian.Uint16(data[66:68]),
+		BufferCapacity:  binary.BigEndian.Uint16(data[68:70]),
+		DecodeErrors:    binary.BigEndian.Uint32(data[70:74]),
+		Quality:         data[74],
+	}
+
+	copy(sr.ListenerIPv6[:], data[0:16])
+	copy(sr.Group[:], data[18:50])
+
+	return sr, nil
+}
+
+// JitterDuration returns the reported interarrival jitter
+func (sr *SignalReportPayload) JitterDuration() time.Duration {
+	return time.Duration(sr.Jitter) * time.Microsecond
+}
+
+// SignalQuality scores reception from 0 (nothing heard) to 100 (no loss or errors)
+func SignalQuality(received, lost, decodeErrors uint32) uint8 {
+	expected := uint64(received) + uint64(lost)
+	if expected == 0 {
+		return 0
+	}
+	good := uint64(received)
+	if uint64(decodeErrors) < good {
+		good -= uint64(decodeErrors)
+	} else {
+		good = 0
+	}
+	return uint8(good * 100 / expected)
+}
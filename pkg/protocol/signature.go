@@ -0,0 +1,40 @@
+package protocol
+
+import (
+	"crypto/ed25519"
+)
+
+// SignatureSize is the size of the signature trailer on signed packets
+const SignatureSize = ed25519.SignatureSize
+
+// Sign signs the packet with a station key and sets FlagSigned.
+// Sign after all other fields are final, except SignalQuality and HopCount
+// which are left out of the signature so they can change in transit.
+func (p *Packet) Sign(key ed25519.PrivateKey) {
+	p.Flags |= FlagSigned
+	p.Signature = ed25519.Sign(key, p.signedBytes())
+}
+
+// IsSigned reports whether the packet carries a signature
+func (p *Packet) IsSigned() bool {
+	return p.Flags&FlagSigned != 0 && len(p.Signature) == SignatureSize
+}
+
+// Verify checks the packet signature against a public key
+func (p *Packet) Verify(key ed25519.PublicKey) bool {
+	if !p.IsSigned() || len(key) != ed25519.PublicKeySize {
+		return false
+	}
+	return ed25519.Verify(key, p.signedBytes(), p.Signature)
+}
+
+// signedBytes returns the encoded packet covered by the signature
+func (p *Packet) signedBytes() []byte {
+	unsigned := *p
+	unsigned.Signature = nil
+	unsigned.SignalQuality = 0
+	unsigned.HopCount = 0
+
+	buf, _ := unsigned.Marshal()
+	return buf
+}
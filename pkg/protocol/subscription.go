@@ -3,6 +3,22 @@ package protocol
 import (
 	"encoding/binary"
 	"net"
+	"time"
+)
+
+// Subscribe acknowledgement results
+const (
+	SubscribeAccepted  uint8 = 0x00 // Subscription active
+	RejectUnknownGroup uint8 = 0x01 // No broadcaster serves the requested group
+	RejectFull         uint8 = 0x02 // Broadcaster reached its listener limit
+	RejectBanned       uint8 = 0x03 // Listener is banned from the group
+	RejectKicked       uint8 = 0x04 // Broadcaster ended an active subscription
+	RejectDenied       uint8 = 0x05 // Listener is not on the group's allow list, or on its deny list
+)
+
+// Heartbeat flags
+const (
+	HeartbeatFlagNativeMulticast uint8 = 0x01 // Listener receives the native IPv6 multicast stream
 )
 
 // SubscribePayload represents a listener subscription request
@@ -12,30 +28,59 @@ type SubscribePayload struct {
 	Callsign     [16]byte
 	Group        [32]byte // Multicast group name (e.g., "emergency", "community")
 	SSMSource    [16]byte // SSM source IPv6 (all zeros = regular multicast)
+	Cookie       [16]byte // Echo of the broadcaster's challenge (all zeros = first attempt)
 }
 
 // HeartbeatPayload represents a keepalive from listener
 type HeartbeatPayload struct {
 	ListenerIPv6 [16]byte
 	Timestamp    uint64
+	ListenerPort uint16 // 0 = any port of ListenerIPv6 (old format)
+	Flags        uint8  // HeartbeatFlag* bits
+}
+
+// UnsubscribePayload represents a listener leaving a group
+type UnsubscribePayload struct {
+	ListenerIPv6 [16]byte
+	ListenerPort uint16
+	Group        [32]byte // Group to leave (all zeros = broadcaster's default group)
+}
+
+// SubscribeChallengePayload is the broadcaster's return-routability check.
+// The listener proves it receives at its address by echoing the cookie in a new SUBSCRIBE.
+type SubscribeChallengePayload struct {
+	Cookie [16]byte
+	Group  [32]byte
+}
+
+// SubscribeAckPayload represents a broadcaster's answer to a SUBSCRIBE
+// On acceptance it carries the lease the listener must honour
+type SubscribeAckPayload struct {
+	Result            uint8    // SubscribeAccepted or a Reject* code
+	Group             [32]byte // Group the listener was accepted into
+	HeartbeatInterval uint32   // Milliseconds between listener heartbeats
+	LeaseTimeout      uint32   // Milliseconds without heartbeat before pruning
+	MulticastGroup    [16]byte // Native IPv6 multicast group (all zeros = unicast only)
+	MulticastPort     uint16
 }
 
 // MarshalSubscribe encodes subscription payload to bytes
 func MarshalSubscribe(sp *SubscribePayload) []byte {
-	buf := make([]byte, 82) // 16 + 2 + 16 + 32 + 16
+	buf := make([]byte, 98) // 16 + 2 + 16 + 32 + 16 + 16
 
 	copy(buf[0:16], sp.ListenerIPv6[:])
 	binary.BigEndian.PutUint16(buf[16:18], sp.ListenerPort)
 	copy(buf[18:34], sp.Callsign[:])
 	copy(buf[34:66], sp.Group[:])
 	copy(buf[66:82], sp.SSMSource[:])
+	copy(buf[82:98], sp.Cookie[:])
 
 	return buf
 }
 
 // UnmarshalSubscribe decodes subscription payload from bytes
 func UnmarshalSubscribe(data []byte) (*SubscribePayload, error) {
-	// Support both old (34 bytes) and new (82 bytes) formats
+	// Support old (34 bytes), group (82 bytes) and cookie (98 bytes) formats
 	if len(data) < 34 {
 		return nil, ErrInvalidPayload
 	}
@@ -53,15 +98,22 @@ func UnmarshalSubscribe(data []byte) (*SubscribePayload, error) {
 		copy(sp.SSMSource[:], data[66:82])
 	}
 
+	// If format with challenge cookie
+	if len(data) >= 98 {
+		copy(sp.Cookie[:], data[82:98])
+	}
+
 	return sp, nil
 }
 
 // MarshalHeartbeat encodes heartbeat payload to bytes
 func MarshalHeartbeat(hp *HeartbeatPayload) []byte {
-	buf := make([]byte, 24) // 16 + 8
+	buf := make([]byte, 27) // 16 + 8 + 2 + 1
 
 	copy(buf[0:16], hp.ListenerIPv6[:])
 	binary.BigEndian.PutUint64(buf[16:24], hp.Timestamp)
+	binary.BigEndian.PutUint16(buf[24:26], hp.ListenerPort)
+	buf[26] = hp.Flags
 
 	return buf
 }
@@ -78,9 +130,132 @@ func UnmarshalHeartbeat(data []byte) (*HeartbeatPayload, error) {
 
 	copy(hp.ListenerIPv6[:], data[0:16])
 
+	// If format with port and flags
+	if len(data) >= 27 {
+		hp.ListenerPort = binary.BigEndian.Uint16(data[24:26])
+		hp.Flags = data[26]
+	}
+
 	return hp, nil
 }
 
+// MarshalUnsubscribe encodes unsubscribe payload to bytes
+func MarshalUnsubscribe(up *UnsubscribePayload) []byte {
+	buf := make([]byte, 50) // 16 + 2 + 32
+
+	copy(buf[0:16], up.ListenerIPv6[:])
+	binary.BigEndian.PutUint16(buf[16:18], up.ListenerPort)
+	copy(buf[18:50], up.Group[:])
+
+	return buf
+}
+
+// UnmarshalUnsubscribe decodes unsubscribe payload from bytes
+func UnmarshalUnsubscribe(data []byte) (*UnsubscribePayload, error) {
+	if len(data) < 50 {
+		return nil, ErrInvalidPayload
+	}
+
+	up := &UnsubscribePayload{
+		ListenerPort: binary.BigEndian.Uint16(data[16:18]),
+	}
+
+	copy(up.ListenerIPv6[:], data[0:16])
+	copy(up.Group[:], data[18:50])
+
+	return up, nil
+}
+
+// MarshalSubscribeAck encodes subscribe acknowledgement payload to bytes
+func MarshalSubscribeAck(ap *SubscribeAckPayload) []byte {
+	buf := make([]byte, 59) // 1 + 32 + 4 + 4 + 16 + 2
+
+	buf[0] = ap.Result
+	copy(buf[1:33], ap.Group[:])
+	binary.BigEndian.PutUint32(buf[33:37], ap.HeartbeatInterval)
+	binary.BigEndian.PutUint32(buf[37:41], ap.LeaseTimeout)
+	copy(buf[41:57], ap.MulticastGroup[:])
+	binary.BigEndian.PutUint16(buf[57:59], ap.MulticastPort)
+
+	return buf
+}
+
+// UnmarshalSubscribeAck decodes subscribe acknowledgement payload from bytes
+func UnmarshalSubscribeAck(data []byte) (*SubscribeAckPayload, error) {
+	if len(data) < 41 {
+		return nil, ErrInvalidPayload
+	}
+
+	ap := &SubscribeAckPayload{
+		Result:            data[0],
+		HeartbeatInterval: binary.BigEndian.Uint32(data[33:37]),
+		LeaseTimeout:      binary.BigEndian.Uint32(data[37:41]),
+	}
+
+	copy(ap.Group[:], data[1:33])
+
+	// If format with native multicast group
+	if len(data) >= 59 {
+		copy(ap.MulticastGroup[:], data[41:57])
+		ap.MulticastPort = binary.BigEndian.Uint16(data[57:59])
+	}
+
+	return ap, nil
+}
+
+// MarshalSubscribeChallenge encodes subscribe challenge payload to bytes
+func MarshalSubscribeChallenge(cp *SubscribeChallengePayload) []byte {
+	buf := make([]byte, 48) // 16 + 32
+
+	copy(buf[0:16], cp.Cookie[:])
+	copy(buf[16:48], cp.Group[:])
+
+	return buf
+}
+
+// UnmarshalSubscribeChallenge decodes subscribe challenge payload from bytes
+func UnmarshalSubscribeChallenge(data []byte) (*SubscribeChallengePayload, error) {
+	if len(data) < 48 {
+		return nil, ErrInvalidPayload
+	}
+
+	cp := &SubscribeChallengePayload{}
+	copy(cp.Cookie[:], data[0:16])
+	copy(cp.Group[:], data[16:48])
+
+	return cp, nil
+}
+
+// HeartbeatDuration returns the negotiated heartbeat interval
+func (ap *SubscribeAckPayload) HeartbeatDuration() time.Duration {
+	return time.Duration(ap.HeartbeatInterval) * time.Millisecond
+}
+
+// LeaseDuration returns the negotiated lease timeout
+func (ap *SubscribeAckPayload) LeaseDuration() time.Duration {
+	return time.Duration(ap.LeaseTimeout) * time.Millisecond
+}
+
+// RejectReasonString returns a human-readable subscribe result
+func RejectReasonString(result uint8) string {
+	switch result {
+	case SubscribeAccepted:
+		return "accepted"
+	case RejectUnknownGroup:
+		return "unknown group"
+	case RejectFull:
+		return "broadcaster full"
+	case RejectBanned:
+		return "banned"
+	case RejectKicked:
+		return "kicked"
+	case RejectDenied:
+		return "not allowed"
+	default:
+		return "unknown reason"
+	}
+}
+
 // Helper to convert net.IP to [16]byte
 func IPv6ToBytes(ip net.IP) [16]byte {
 	var result [16]byte
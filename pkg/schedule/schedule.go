@@ -0,0 +1,289 @@
+package schedule
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kind says what a slot plays
+type Kind string
+
+const (
+	KindPlaylist Kind = "playlist" // Music files from a directory, in a loop
+	KindLive     Kind = "live"     // A live input ("mic")
+	KindSilence  Kind = "silence"  // Nothing, the stream stays up
+	KindID       Kind = "id"       // A station ID file once, then silence
+)
+
+// Slot is one line of the timetable
+type Slot struct {
+	Days   [7]bool       // Days the slot starts on, indexed by time.Weekday
+	Start  time.Duration // Since midnight
+	End    time.Duration // Since midnight (up to 24h); at or before Start = runs past midnight
+	Kind   Kind
+	Source string // Playlist directory, live input or ID file
+	Title  string // Programme name shown to listeners
+	Line   int    // Line in the schedule file (0 = not from a file)
+}
+
+// Schedule is a weekly programme grid
+// Slots may overlap: the first matching slot in file order wins, so list
+// shows before the all-week fallback.
+type Schedule struct {
+	Slots []Slot
+}
+
+var dayNames = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Load reads a schedule file.
+// Each line is "DAYS START-END KIND [SOURCE] [| TITLE]", for example
+//
+//	mon-fri  06:00-09:00  playlist  /srv/music/morning  | Morning Show
+//	sat      22:00-02:00  live      mic                 | Late Night Live
+//	*        00:00-24:00  playlist  /srv/music/rotation
+//
+// DAYS is "*" or "daily", or a comma list of days and day ranges (mon,wed,fri-sun).
+// Blank lines and # comments are ignored.
+func Load(path string) (*Schedule, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open schedule: %w", err)
+	}
+	defer f.Close()
+
+	s, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", path, err)
+	}
+	return s, nil
+}
+
+// Parse reads a schedule in the Load format
+func Parse(r io.Reader) (*Schedule, error) {
+	s := &Schedule{}
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		slot, err := parseSlot(line)
+		if err != nil {
+			return nil, fmt.Errorf("%d: %w", lineNum, err)
+		}
+		slot.Line = lineNum
+		s.Slots = append(s.Slots, slot)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read schedule: %w", err)
+	}
+
+	return s, nil
+}
+
+// parseSlot parses one timetable line
+func parseSlot(line string) (Slot, error) {
+	var slot Slot
+
+	// Title: everything after "|"
+	if i := strings.Index(line, "|"); i >= 0 {
+		slot.Title = strings.TrimSpace(line[i+1:])
+		line = strings.TrimSpace(line[:i])
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) < 3 {
+		return slot, fmt.Errorf("expected \"DAYS START-END KIND [SOURCE] [| TITLE]\"")
+	}
+
+	days, err := parseDays(fields[0])
+	if err != nil {
+		return slot, err
+	}
+	slot.Days = days
+
+	start, end, ok := strings.Cut(fields[1], "-")
+	if !ok {
+		return slot, fmt.Errorf("invalid time range %q, expected HH:MM-HH:MM", fields[1])
+	}
+	if slot.Start, err = parseClock(start); err != nil {
+		return slot, err
+	}
+	if slot.End, err = parseClock(end); err != nil {
+		return slot, err
+	}
+	if slot.Start == 24*time.Hour {
+		return slot, fmt.Errorf("slot can't start at 24:00")
+	}
+
+	// The source is the rest of the line, so paths may contain spaces
+	slot.Kind = Kind(strings.ToLower(fields[2]))
+	if len(fields) > 3 {
+		rest := strings.TrimSpace(line[strings.Index(line, fields[1])+len(fields[1]):])
+		slot.Source = strings.TrimSpace(rest[len(fields[2]):])
+	}
+
+	switch slot.Kind {
+	case KindPlaylist, KindID:
+		if slot.Source == "" {
+			return slot, fmt.Errorf("%s slot needs a file or directory", slot.Kind)
+		}
+	case KindLive:
+		if slot.Source == "" {
+			slot.Source = "mic"
+		}
+		if slot.Source != "mic" {
+			return slot, fmt.Errorf("unknown live input %q (only \"mic\")", slot.Source)
+		}
+	case KindSilence:
+	default:
+		return slot, fmt.Errorf("unknown slot kind %q", fields[2])
+	}
+
+	return slot, nil
+}
+
+// parseDays parses "*", "daily" or a comma list of days and ranges
+func parseDays(s string) ([7]bool, error) {
+	var days [7]bool
+
+	s = strings.ToLower(s)
+	if s == "*" || s == "daily" {
+		for i := range days {
+			days[i] = true
+		}
+		return days, nil
+	}
+
+	for _, part := range strings.Split(s, ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		first, ok := dayNames[from]
+		if !ok {
+			return days, fmt.Errorf("unknown day %q", from)
+		}
+		last := first
+		if isRange {
+			if last, ok = dayNames[to]; !ok {
+				return days, fmt.Errorf("unknown day %q", to)
+			}
+		}
+
+		// Ranges may wrap around the week (fri-mon)
+		for d := first; ; d = (d + 1) % 7 {
+			days[d] = true
+			if d == last {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+// parseClock parses HH:MM (00:00 to 24:00) into time since midnight
+func parseClock(s string) (time.Duration, error) {
+	hh, mm, ok := strings.Cut(s, ":")
+	if !ok {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	h, err1 := strconv.Atoi(hh)
+	m, err2 := strconv.Atoi(mm)
+	if err1 != nil || err2 != nil || h < 0 || h > 24 || m < 0 || m > 59 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+// At returns the slot on air at t (nil = none)
+func (s *Schedule) At(t time.Time) *Slot {
+	for i := range s.Slots {
+		if _, ok := s.Slots[i].occurrence(t); ok {
+			return &s.Slots[i]
+		}
+	}
+	return nil
+}
+
+// NextChange returns when the slot on air after t changes next
+// Returns the zero time if the grid never changes (one slot all week, or none).
+func (s *Schedule) NextChange(t time.Time) time.Time {
+	current := s.At(t)
+
+	// Every slot boundary in the coming week is a candidate
+	var candidates []time.Time
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	for day := -1; day <= 7; day++ {
+		date := midnight.AddDate(0, 0, day)
+		for _, slot := range s.Slots {
+			if !slot.Days[date.Weekday()] {
+				continue
+			}
+			start, end := slot.bounds(date)
+			candidates = append(candidates, start, end)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+
+	for _, c := range candidates {
+		if c.After(t) && s.At(c) != current {
+			return c
+		}
+	}
+	return time.Time{}
+}
+
+// String describes the slot for logs
+func (slot *Slot) String() string {
+	name := slot.Title
+	if name == "" {
+		name = string(slot.Kind)
+	}
+	if slot.Source != "" {
+		return fmt.Sprintf("%s (%s %s)", name, slot.Kind, slot.Source)
+	}
+	return fmt.Sprintf("%s (%s)", name, slot.Kind)
+}
+
+// occurrence returns the start of the slot occurrence covering t, if any
+func (slot *Slot) occurrence(t time.Time) (time.Time, bool) {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+
+	// Started today, or yesterday and running past midnight
+	for _, date := range []time.Time{midnight, midnight.AddDate(0, 0, -1)} {
+		if !slot.Days[date.Weekday()] {
+			continue
+		}
+		start, end := slot.bounds(date)
+		if !t.Before(start) && t.Before(end) {
+			return start, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// bounds returns the absolute start and end of the slot starting on date
+func (slot *Slot) bounds(date time.Time) (time.Time, time.Time) {
+	start := date.Add(slot.Start)
+	end := date.Add(slot.End)
+	if slot.End <= slot.Start {
+		end = date.AddDate(0, 0, 1).Add(slot.End)
+	}
+	return start, end
+}
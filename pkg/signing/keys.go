@@ -0,0 +1,137 @@
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/meshradio/meshradio/pkg/yggdrasil"
+)
+
+// DefaultYggdrasilConfig is where Yggdrasil packages install the node config
+const DefaultYggdrasilConfig = "/etc/yggdrasil/yggdrasil.conf"
+
+// yggdrasilKeyPattern matches the PrivateKey entry in HJSON or JSON configs
+var yggdrasilKeyPattern = regexp.MustCompile(`"?PrivateKey"?\s*:\s*"?([0-9a-fA-F]{128})`)
+
+// GenerateKey creates a new station keypair
+func GenerateKey() (ed25519.PrivateKey, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	return key, nil
+}
+
+// LoadKey reads a station key file (hex-encoded 32-byte seed or 64-byte private key)
+func LoadKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	raw, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid key file %s: %w", path, err)
+	}
+
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	default:
+		return nil, fmt.Errorf("invalid key file %s: %d bytes", path, len(raw))
+	}
+}
+
+// SaveKey writes a station key file readable only by the owner
+func SaveKey(path string, key ed25519.PrivateKey) error {
+	data := hex.EncodeToString(key.Seed()) + "\n"
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		return fmt.Errorf("failed to write key file: %w", err)
+	}
+	return nil
+}
+
+// LoadOrCreateKey loads a station key, generating and saving one if the file doesn't exist
+func LoadOrCreateKey(path string) (ed25519.PrivateKey, error) {
+	key, err := LoadKey(path)
+	if err == nil {
+		return key, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	key, err = GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	if err := SaveKey(path, key); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("🔑 Generated new station key: %s\n", path)
+	return key, nil
+}
+
+// LoadYggdrasilKey reads the node's private key from a Yggdrasil config file,
+// so the station signs with the same key its mesh address is derived from
+func LoadYggdrasilKey(configPath string) (ed25519.PrivateKey, error) {
+	if configPath == "" {
+		configPath = DefaultYggdrasilConfig
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Yggdrasil config: %w", err)
+	}
+
+	match := yggdrasilKeyPattern.FindSubmatch(data)
+	if match == nil {
+		return nil, fmt.Errorf("no PrivateKey found in %s", configPath)
+	}
+
+	raw, err := hex.DecodeString(string(match[1]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Yggdrasil private key: %w", err)
+	}
+
+	return ed25519.PrivateKey(raw), nil
+}
+
+// YggdrasilPublicKey returns the local node's public key as reported by yggdrasilctl
+func YggdrasilPublicKey() (ed25519.PublicKey, error) {
+	self, err := yggdrasil.NewClient().GetSelf()
+	if err != nil {
+		return nil, err
+	}
+	if self.PublicKey == "" {
+		return nil, fmt.Errorf("yggdrasilctl did not report a public key")
+	}
+	return ParsePublicKey(self.PublicKey)
+}
+
+// ParsePublicKey decodes a hex-encoded public key
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	raw, err := hex.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	if len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key: %d bytes", len(raw))
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+// PublicKeyHex returns the hex-encoded public key of a station key,
+// the form used in trust store files
+func PublicKeyHex(key ed25519.PrivateKey) string {
+	return hex.EncodeToString(key.Public().(ed25519.PublicKey))
+}
@@ -0,0 +1,132 @@
+package signing
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/meshradio/meshradio/pkg/protocol"
+)
+
+// Verdict is the result of checking a packet against the trust store
+type Verdict int
+
+const (
+	Unsigned  Verdict = iota // No signature
+	Untrusted                // Signed, but no trusted key for the callsign verifies it
+	Trusted                  // Signed by a trusted key for the callsign
+)
+
+// String returns the string representation of the verdict
+func (v Verdict) String() string {
+	switch v {
+	case Unsigned:
+		return "unsigned"
+	case Untrusted:
+		return "untrusted"
+	case Trusted:
+		return "trusted"
+	default:
+		return "unknown"
+	}
+}
+
+// TrustStore maps station callsigns to the public keys allowed to sign for them
+type TrustStore struct {
+	keys map[string][]ed25519.PublicKey // key: upper-case callsign
+	mu   sync.RWMutex
+}
+
+// NewTrustStore creates an empty trust store
+func NewTrustStore() *TrustStore {
+	return &TrustStore{
+		keys: make(map[string][]ed25519.PublicKey),
+	}
+}
+
+// LoadTrustStore reads a trust store file.
+// Each line is "CALLSIGN <hex public key>"; blank lines and # comments are ignored.
+func LoadTrustStore(path string) (*TrustStore, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open trust store: %w", err)
+	}
+	defer f.Close()
+
+	ts := NewTrustStore()
+	scanner := bufio.NewScanner(f)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected \"CALLSIGN PUBLICKEY\"", path, lineNum)
+		}
+
+		key, err := ParsePublicKey(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNum, err)
+		}
+		ts.Add(fields[0], key)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read trust store: %w", err)
+	}
+
+	return ts, nil
+}
+
+// Add trusts a public key for a callsign (a callsign may have several keys)
+func (ts *TrustStore) Add(callsign string, key ed25519.PublicKey) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	callsign = strings.ToUpper(callsign)
+	for _, k := range ts.keys[callsign] {
+		if k.Equal(key) {
+			return
+		}
+	}
+	ts.keys[callsign] = append(ts.keys[callsign], key)
+}
+
+// Remove forgets all keys for a callsign
+func (ts *TrustStore) Remove(callsign string) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	delete(ts.keys, strings.ToUpper(callsign))
+}
+
+// Len returns the number of trusted callsigns
+func (ts *TrustStore) Len() int {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	return len(ts.keys)
+}
+
+// Check verifies a packet's signature against the keys trusted for its callsign
+func (ts *TrustStore) Check(packet *protocol.Packet) Verdict {
+	if !packet.IsSigned() {
+		return Unsigned
+	}
+
+	ts.mu.RLock()
+	keys := ts.keys[strings.ToUpper(packet.GetCallsign())]
+	ts.mu.RUnlock()
+
+	for _, key := range keys {
+		if packet.Verify(key) {
+			return Trusted
+		}
+	}
+	return Untrusted
+}
@@ -410,6 +410,11 @@ func (m Model) renderListen() string {
 	var stationInfo string
 	var signalBar string
 	if m.listener != nil {
+		// Voice channel in a pause between transmissions
+		if m.listener.GetReceptionStats().Silent {
+			status = statusStyle.Render("● LISTENING (silence)")
+		}
+
 		packets, seq, station := m.listener.GetStats()
 		if station != "" {
 			stationInfo = fmt.Sprintf("Station: %s", station)
@@ -420,7 +425,29 @@ func (m Model) renderListen() string {
 			stationInfo = "Station: Waiting for signal..."
 			signalBar = "░░░░░░░░░░"
 		}
+		if md, ok := m.listener.GetMetadata(); ok {
+			stationInfo += fmt.Sprintf("\nNow playing: %s", md.DisplayTitle())
+			if md.Duration > 0 {
+				stationInfo += fmt.Sprintf(" [%s / %s]",
+					md.Position.Round(time.Second), md.Duration.Round(time.Second))
+			}
+			if md.StationText != "" {
+				stationInfo += fmt.Sprintf("\n%s", md.StationText)
+			}
+		}
 		stationInfo += fmt.Sprintf("\nPackets: %d | Sequence: %d", packets, seq)
+
+		// The station signed off: say so instead of waiting for a signal
+		if oa, ok := m.listener.OffAir(); ok {
+			status = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("214")).
+				Bold(true).
+				Render("○ STATION OFF AIR")
+			stationInfo += fmt.Sprintf("\nOff air: %s", oa.ReasonString())
+			if !oa.BackAt.IsZero() {
+				stationInfo += fmt.Sprintf(", back at %s", oa.BackAt.Format("15:04"))
+			}
+		}
 	}
 
 	info := fmt.Sprintf(`
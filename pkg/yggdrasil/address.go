@@ -0,0 +1,51 @@
+package yggdrasil
+
+import (
+	"crypto/ed25519"
+	"net"
+)
+
+// addressPrefix is the first byte of every Yggdrasil node address (200::/7)
+const addressPrefix = 0x02
+
+// AddressForKey returns the Yggdrasil node address derived from a public key
+// The address is 0x02, the number of leading ones of the inverted key, then
+// the bits after the first zero. Yggdrasil routes an address only to the
+// node holding its key, so a listener that answered from this address holds
+// the key. Returns nil for a malformed key.
+func AddressForKey(key ed25519.PublicKey) net.IP {
+	if len(key) != ed25519.PublicKeySize {
+		return nil
+	}
+
+	addr := make(net.IP, net.IPv6len)
+	addr[0] = addressPrefix
+
+	var ones byte
+	done := false
+	pos := 2 // Next byte of addr to fill
+	var bits byte
+	nBits := 0
+	for i := 0; i < 8*len(key) && pos < len(addr); i++ {
+		bit := (^key[i/8] >> (7 - uint(i%8))) & 1
+		if !done {
+			if bit == 1 {
+				ones++
+			} else {
+				done = true
+			}
+			continue
+		}
+
+		bits = bits<<1 | bit
+		nBits++
+		if nBits == 8 {
+			addr[pos] = bits
+			pos++
+			bits, nBits = 0, 0
+		}
+	}
+	addr[1] = ones
+
+	return addr
+}